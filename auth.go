@@ -0,0 +1,232 @@
+package respond
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Error codes of RFC 6750 for the error parameter of challenges
+const (
+	ChallengeInvalidRequest    = "invalid_request"
+	ChallengeInvalidToken      = "invalid_token"
+	ChallengeInsufficientScope = "insufficient_scope"
+)
+
+// Challenge configures the WWW-Authenticate header of the auth errors
+// as described in RFC 6750
+type Challenge struct {
+	// Scheme of the challenge, Bearer is used when it is empty
+	Scheme string
+
+	// Realm and Scope parameters of the challenge, they are left out
+	// when empty
+	Realm string
+	Scope string
+
+	// Error and ErrorDescription replace the error and
+	// error_description parameters picked for the error. The english
+	// message of the error is the description by default
+	Error            string
+	ErrorDescription string
+}
+
+// The error parameter of the auth errors, errors without credentials
+// have no error parameter as RFC 6750 requires
+var challengeErrors = map[int]string{
+	3001: "",
+	3005: ChallengeInvalidToken,
+	3006: "",
+	3007: ChallengeInvalidToken,
+	3010: ChallengeInvalidToken,
+	3011: ChallengeInvalidToken,
+	3012: ChallengeInvalidToken,
+	3013: ChallengeInvalidToken,
+	3014: ChallengeInvalidToken,
+	3015: ChallengeInvalidToken,
+	5401: "",
+	5403: ChallengeInsufficientScope,
+	5422: ChallengeInvalidToken,
+}
+
+// Set the WWW-Authenticate challenge of the auth errors of the response
+//
+//      r.SetChallenge(respond.Challenge{Realm: "api"}).TokenExpired()
+//
+// @param challenge Challenge
+// @return *Respond
+func (r *Respond) SetChallenge(challenge Challenge) *Respond {
+	r.challenge = challenge
+	return r
+}
+
+// Prepare the WWW-Authenticate header of an auth error, every 401
+// response gets a challenge. The header is set when the response is
+// written with the status of the error, so the response written instead
+// of it, like a 500 after an encoding error, has no challenge
+func (r *Respond) prepareChallenge(statusCode, errorCode int, key string, args Args) {
+	code, ok := challengeErrors[errorCode]
+	if !ok && statusCode != http.StatusUnauthorized {
+		return
+	}
+	c := r.challenge
+	if c.Scheme == "" {
+		c.Scheme = "Bearer"
+	}
+	if c.Error != "" {
+		code = c.Error
+	}
+	description := c.ErrorDescription
+	if description == "" && code != "" {
+		description = r.challengeDescription(key, args)
+	}
+
+	var params []string
+	for _, p := range [][2]string{
+		{"realm", c.Realm},
+		{"scope", c.Scope},
+		{"error", code},
+		{"error_description", description},
+	} {
+		if p[1] != "" {
+			params = append(params, p[0]+`="`+challengeValue(p[1])+`"`)
+		}
+	}
+	r.challengeStatus, r.challengeHeader = statusCode, c.Scheme
+	if len(params) > 0 {
+		r.challengeHeader += " " + strings.Join(params, ", ")
+	}
+}
+
+// Set the prepared WWW-Authenticate header when the response has the
+// status of the challenge, a header set by the handler is kept
+func (r *Respond) writeChallenge(header http.Header) {
+	if r.challengeHeader == "" || r.statusCode != r.challengeStatus || header.Get("WWW-Authenticate") != "" {
+		return
+	}
+	header.Set("WWW-Authenticate", r.challengeHeader)
+}
+
+// Get the message of the default language as the description, RFC 6750
+// only allows printable ASCII so other messages are left out
+func (r *Respond) challengeDescription(key string, args Args) string {
+	message, lang, ok := r.catalog().catalog().find(DefaultLanguage, key, args)
+	if !ok {
+		return ""
+	}
+	message = interpolate(message, lang, args)
+	for _, c := range message {
+		if c < 0x20 || c > 0x7e {
+			return ""
+		}
+	}
+	return message
+}
+
+func challengeValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value)
+}
+
+// The user is not logged on
+//
+// @param args ...Args
+// @return error
+func (r *Respond) NotLoggedOn(args ...Args) error {
+	return r.catalogError(ErrNotLoggedOn.Status, ErrNotLoggedOn.Code, args...)
+}
+
+// The token of the request has no user
+//
+// @param args ...Args
+// @return error
+func (r *Respond) TokenWithoutUser(args ...Args) error {
+	return r.catalogError(ErrTokenWithoutUser.Status, ErrTokenWithoutUser.Code, args...)
+}
+
+// The request has no token
+//
+// @param args ...Args
+// @return error
+func (r *Respond) TokenNotSet(args ...Args) error {
+	return r.catalogError(ErrTokenNotSet.Status, ErrTokenNotSet.Code, args...)
+}
+
+// The token of the request can not be decoded
+//
+// @param args ...Args
+// @return error
+func (r *Respond) TokenDecodeFailed(args ...Args) error {
+	return r.catalogError(ErrTokenDecodeFailed.Status, ErrTokenDecodeFailed.Code, args...)
+}
+
+// The token of the request is expired
+//
+// @param args ...Args
+// @return error
+func (r *Respond) TokenExpired(args ...Args) error {
+	return r.catalogError(ErrTokenExpired.Status, ErrTokenExpired.Code, args...)
+}
+
+// The token of the request is invalid
+//
+// @param args ...Args
+// @return error
+func (r *Respond) TokenInvalid(args ...Args) error {
+	return r.catalogError(ErrTokenInvalid.Status, ErrTokenInvalid.Code, args...)
+}
+
+// The token of the request is blacklisted
+//
+// @param args ...Args
+// @return error
+func (r *Respond) TokenBlacklisted(args ...Args) error {
+	return r.catalogError(ErrTokenBlacklisted.Status, ErrTokenBlacklisted.Code, args...)
+}
+
+// The payload of the token is invalid
+//
+// @param args ...Args
+// @return error
+func (r *Respond) PayloadInvalid(args ...Args) error {
+	return r.catalogError(ErrPayloadInvalid.Status, ErrPayloadInvalid.Code, args...)
+}
+
+// A claim of the token is invalid
+//
+// @param args ...Args
+// @return error
+func (r *Respond) ClaimInvalid(args ...Args) error {
+	return r.catalogError(ErrClaimInvalid.Status, ErrClaimInvalid.Code, args...)
+}
+
+// The validation of the token failed
+//
+// @param args ...Args
+// @return error
+func (r *Respond) TokenValidationFailed(args ...Args) error {
+	return r.catalogError(ErrTokenValidationFailed.Status, ErrTokenValidationFailed.Code, args...)
+}
+
+// The request is not authenticated
+//
+// @param args ...Args
+// @return error
+func (r *Respond) Unauthorized(args ...Args) error {
+	return r.catalogError(ErrUnauthorized.Status, ErrUnauthorized.Code, args...)
+}
+
+// The token of the request does not have the scope of the resource, the
+// scope of the challenge names the required scope
+//
+// @param args ...Args
+// @return error
+func (r *Respond) Forbidden(args ...Args) error {
+	return r.catalogError(ErrForbidden.Status, ErrForbidden.Code, args...)
+}
+
+// The token of the request is not valid
+//
+// @param args ...Args
+// @return error
+func (r *Respond) TokenNotValid(args ...Args) error {
+	return r.catalogError(ErrTokenNotValid.Status, ErrTokenNotValid.Code, args...)
+}
@@ -0,0 +1,134 @@
+package respond
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthHelpers(t *testing.T) {
+
+	t.Parallel()
+
+	for name, test := range map[string]struct {
+		call   func(r *Respond, args ...Args) error
+		status int
+		code   int
+		header string
+	}{
+		"NotLoggedOn":      {(*Respond).NotLoggedOn, 401, 3001, `Bearer`},
+		"TokenNotSet":      {(*Respond).TokenNotSet, 401, 3006, `Bearer`},
+		"Unauthorized":     {(*Respond).Unauthorized, 401, 5401, `Bearer`},
+		"TokenExpired":     {(*Respond).TokenExpired, 401, 3010, `Bearer error="invalid_token", error_description="Token expired!"`},
+		"TokenInvalid":     {(*Respond).TokenInvalid, 401, 3011, `Bearer error="invalid_token", error_description="Token is invalid!"`},
+		"TokenBlacklisted": {(*Respond).TokenBlacklisted, 401, 3012, `Bearer error="invalid_token", error_description="Token Blacklisted"`},
+		"ClaimInvalid":     {(*Respond).ClaimInvalid, 401, 3014, `Bearer error="invalid_token", error_description="Claim Invalid"`},
+		"TokenNotValid":    {(*Respond).TokenNotValid, 401, 5422, `Bearer error="invalid_token", error_description="Token is not valid"`},
+		"Forbidden": {(*Respond).Forbidden, 403, 5403,
+			`Bearer error="insufficient_scope", error_description="You do not have permission to access the requested resource"`},
+	} {
+		recorder := httptest.NewRecorder()
+		assert.NoError(t, test.call(NewWithWriter(recorder)), name)
+
+		assert.Equal(t, test.status, recorder.Code, name)
+		assert.Equal(t, test.header, recorder.Header().Get("WWW-Authenticate"), name)
+		assert.Contains(t, recorder.Body.String(), fmt.Sprintf(`"error":%d`, test.code), name)
+	}
+}
+
+func TestChallenge(t *testing.T) {
+
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+	assert.NoError(t, NewWithWriter(recorder).
+		SetChallenge(Challenge{Realm: `my "api"`, Scope: "users:write"}).
+		Language("fa").
+		Forbidden())
+
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+	assert.Equal(t, `Bearer realm="my \"api\"", scope="users:write", error="insufficient_scope", `+
+		`error_description="You do not have permission to access the requested resource"`,
+		recorder.Header().Get("WWW-Authenticate"))
+
+	recorder = httptest.NewRecorder()
+	assert.NoError(t, NewWithWriter(recorder).
+		SetChallenge(Challenge{Scheme: "DPoP", Error: "invalid_dpop_proof", ErrorDescription: "proof is stale"}).
+		TokenExpired())
+
+	assert.Equal(t, `DPoP error="invalid_dpop_proof", error_description="proof is stale"`, recorder.Header().Get("WWW-Authenticate"))
+}
+
+func TestChallengeKeepsHandlerHeader(t *testing.T) {
+
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+	recorder.Header().Set("WWW-Authenticate", `Basic realm="admin"`)
+	assert.NoError(t, NewWithWriter(recorder).Err(fmt.Errorf("login: %w", ErrNotLoggedOn)))
+
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	assert.Equal(t, `Basic realm="admin"`, recorder.Header().Get("WWW-Authenticate"))
+}
+
+func TestChallengeOfOtherErrors(t *testing.T) {
+
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+	assert.NoError(t, NewWithWriter(recorder).Error(http.StatusUnauthorized, 9401))
+	assert.Equal(t, "Bearer", recorder.Header().Get("WWW-Authenticate"))
+
+	recorder = httptest.NewRecorder()
+	assert.NoError(t, NewWithWriter(recorder).NotFound())
+	assert.Empty(t, recorder.Header().Get("WWW-Authenticate"))
+}
+
+func TestChallengeOnlyOnWrittenResponse(t *testing.T) {
+
+	t.Parallel()
+
+	// no challenge on the 406 response written instead
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("Accept", "text/html")
+	assert.ErrorIs(t, NewWithRequest(recorder, request).TokenExpired(), ErrNotAcceptable)
+	assert.Equal(t, http.StatusNotAcceptable, recorder.Code)
+	assert.Empty(t, recorder.Header().Get("WWW-Authenticate"))
+
+	// nor on the 500 response written when the body can not be encoded
+	failing := EncoderFunc(func(w io.Writer, v interface{}) error {
+		return errors.New("broken")
+	})
+	recorder = httptest.NewRecorder()
+	request.Header.Set("Accept", "application/broken")
+	assert.Error(t, New(WithEncoder("application/broken", failing)).Request(recorder, request).TokenExpired())
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.Empty(t, recorder.Header().Get("WWW-Authenticate"))
+
+	// nor when the response is already written
+	recorder = httptest.NewRecorder()
+	r := NewWithWriter(recorder)
+	assert.NoError(t, r.NotFound())
+	assert.ErrorIs(t, r.TokenExpired(), ErrAlreadyWritten)
+	assert.Empty(t, recorder.Header().Get("WWW-Authenticate"))
+}
+
+func TestMiddlewareChallenge(t *testing.T) {
+
+	t.Parallel()
+
+	handler := Middleware(Options{Challenge: Challenge{Realm: "api"}})(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		From(req).TokenNotSet()
+	}))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, `Bearer realm="api"`, recorder.Header().Get("WWW-Authenticate"))
+}
@@ -0,0 +1,54 @@
+package respond
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+)
+
+// Buffers bigger than this are not kept in the pool, so a single huge
+// response does not pin its memory for the life of the process
+const maxPooledBufferSize = 64 << 10
+
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+func getBuffer() *bytes.Buffer {
+	b := bufferPool.Get().(*bytes.Buffer)
+	b.Reset()
+	return b
+}
+
+func putBuffer(b *bytes.Buffer) {
+	if b.Cap() > maxPooledBufferSize {
+		return
+	}
+	bufferPool.Put(b)
+}
+
+// Get the Content-Type header of a media type, textual media types are
+// sent with the utf-8 charset
+func contentType(mediaType string) string {
+	if isTextMediaType(mediaType) {
+		return mediaType + "; charset=utf-8"
+	}
+	return mediaType
+}
+
+func isTextMediaType(mediaType string) bool {
+	switch {
+	case strings.HasPrefix(mediaType, "text/"),
+		strings.HasSuffix(mediaType, "/json"),
+		strings.HasSuffix(mediaType, "+json"),
+		strings.HasSuffix(mediaType, "/xml"),
+		strings.HasSuffix(mediaType, "+xml"),
+		strings.HasSuffix(mediaType, "/yaml"),
+		strings.HasSuffix(mediaType, "/x-yaml"),
+		strings.HasSuffix(mediaType, "+yaml"):
+		return true
+	}
+	return false
+}
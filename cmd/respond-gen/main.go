@@ -0,0 +1,458 @@
+// Command respond-gen turns the error catalog into typed Go code, a
+// Code<Name> constant, an Err<Name> registered error and a <Name> helper
+// for every error code, so call sites do not use magic numbers
+//
+//      //go:generate go run github.com/mrjosh/respond.go/cmd/respond-gen -status 6001=429 ./translations
+//
+// The names come from the short field of the errors and the statuses
+// from the table of the built-in codes or the -status flags. The
+// reference catalog is the -lang catalog of the arguments, or the
+// built-in en catalog without arguments. Names which are already
+// declared in the package, like a hand-written helper, are not generated.
+//
+// The command does not import respond, so it still runs when the package
+// does not build because its generated file is missing or out of date
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/BurntSushi/toml"
+	"github.com/mrjosh/respond.go/translations/en"
+	"github.com/mrjosh/respond.go/translations/fa"
+	"gopkg.in/yaml.v3"
+)
+
+const respondImport = "github.com/mrjosh/respond.go"
+
+// Statuses of the -status flags, like 6001=429
+type statusFlag map[int]int
+
+func (s statusFlag) String() string {
+	return fmt.Sprint(map[int]int(s))
+}
+
+func (s statusFlag) Set(value string) error {
+	for _, pair := range strings.Split(value, ",") {
+		i := strings.IndexByte(pair, '=')
+		if i < 0 {
+			return fmt.Errorf("status %q is not code=status", pair)
+		}
+		code, err := strconv.Atoi(strings.TrimSpace(pair[:i]))
+		if err != nil {
+			return fmt.Errorf("code of %q is not a number", pair)
+		}
+		status, err := strconv.Atoi(strings.TrimSpace(pair[i+1:]))
+		if err != nil || status < 100 || status > 999 {
+			return fmt.Errorf("status of %q is not an HTTP status", pair)
+		}
+		s[code] = status
+	}
+	return nil
+}
+
+// An error code of the catalog
+type code struct {
+	Code    int
+	Name    string
+	Status  int
+	Cat     string
+	Short   string
+	Message string
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stderr))
+}
+
+// Run the generator and get the exit status
+func run(args []string, stderr io.Writer) int {
+	flags := flag.NewFlagSet("respond-gen", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	var (
+		output   = flags.String("o", "codes_gen.go", "output file")
+		pkg      = flags.String("package", "", "package of the output file, detected from the directory of the file by default")
+		lang     = flags.String("lang", "en", "language of the reference catalog")
+		statuses = statusFlag{}
+	)
+	flags.Var(statuses, "status", "HTTP status of unregistered codes, like 6001=429 (repeatable)")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	catalog, err := referenceCatalog(*lang, flags.Args())
+	if err != nil {
+		fmt.Fprintln(stderr, "respond-gen:", err)
+		return 1
+	}
+	dir := filepath.Dir(*output)
+	declared, detected, err := declaredNames(dir, filepath.Base(*output))
+	if err != nil {
+		fmt.Fprintln(stderr, "respond-gen:", err)
+		return 1
+	}
+	if *pkg == "" {
+		*pkg = detected
+	}
+	if *pkg == "" {
+		fmt.Fprintln(stderr, "respond-gen: can not detect the package of", dir, "use -package")
+		return 2
+	}
+
+	codes, err := catalogCodes(catalog, statuses)
+	if err != nil {
+		fmt.Fprintln(stderr, "respond-gen:", err)
+		return 1
+	}
+	source, err := generate(*pkg, *lang, codes, declared)
+	if err != nil {
+		fmt.Fprintln(stderr, "respond-gen:", err)
+		return 1
+	}
+	if err := ioutil.WriteFile(*output, source, 0644); err != nil {
+		fmt.Fprintln(stderr, "respond-gen:", err)
+		return 1
+	}
+	return 0
+}
+
+// Get the catalog of the language from the catalog files and
+// directories, the built-in catalog is used without any
+func referenceCatalog(lang string, names []string) (map[string]interface{}, error) {
+	catalogs := map[string]map[string]interface{}{}
+	if len(names) == 0 {
+		catalogs = map[string]map[string]interface{}{"en": en.Messages, "fa": fa.Messages}
+	}
+	for _, name := range names {
+		info, err := os.Stat(name)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			catalog, err := loadCatalog(name)
+			if err != nil {
+				return nil, err
+			}
+			base := filepath.Base(name)
+			catalogs[strings.TrimSuffix(base, filepath.Ext(base))] = catalog
+			continue
+		}
+		files, err := ioutil.ReadDir(name)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			ext := filepath.Ext(file.Name())
+			if file.IsDir() || !catalogFormats[strings.ToLower(ext)] {
+				continue
+			}
+			catalog, err := loadCatalog(filepath.Join(name, file.Name()))
+			if err != nil {
+				return nil, err
+			}
+			catalogs[strings.TrimSuffix(file.Name(), ext)] = catalog
+		}
+	}
+	catalog, ok := catalogs[lang]
+	if !ok {
+		return nil, fmt.Errorf("no catalog of language %q", lang)
+	}
+	return catalog, nil
+}
+
+// Extensions of the catalog files
+var catalogFormats = map[string]bool{".json": true, ".yaml": true, ".yml": true, ".toml": true}
+
+// Load a catalog file, the files are checked by the loader of respond
+// when they are loaded so only their errors are read here
+func loadCatalog(name string) (map[string]interface{}, error) {
+	data, err := ioutil.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	var catalog map[string]interface{}
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json":
+		err = json.Unmarshal(data, &catalog)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &catalog)
+	case ".toml":
+		_, err = toml.Decode(string(data), &catalog)
+	default:
+		return nil, fmt.Errorf("%s: unsupported catalog format", name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+	return catalog, nil
+}
+
+// Get the error codes of a catalog sorted by code
+func catalogCodes(catalog map[string]interface{}, statuses statusFlag) ([]*code, error) {
+	var (
+		codes    []*code
+		unknown  []string
+		names    = map[string]int{}
+		entries  = level(catalog["errors"])
+		numbered = make([]int, 0, len(entries))
+	)
+	for key := range entries {
+		if n, err := strconv.Atoi(key); err == nil {
+			numbered = append(numbered, n)
+		}
+	}
+	sort.Ints(numbered)
+	for _, n := range numbered {
+		fields := level(entries[strconv.Itoa(n)])
+		c := &code{Code: n}
+		c.Cat, _ = fields["cat"].(string)
+		c.Short, _ = fields["short"].(string)
+		switch message := fields["message"].(type) {
+		case string:
+			c.Message = message
+		default:
+			c.Message, _ = level(message)["other"].(string)
+		}
+		c.Name = identifier(c.Short)
+		if c.Name == "" {
+			c.Name = "Error" + strconv.Itoa(n)
+		}
+		if other, ok := names[c.Name]; ok {
+			return nil, fmt.Errorf("errors %d and %d have the same name %s", other, n, c.Name)
+		}
+		names[c.Name] = n
+
+		if status, ok := statuses[n]; ok {
+			c.Status = status
+		} else if status, ok := builtinStatuses[n]; ok {
+			c.Status = status
+		} else {
+			unknown = append(unknown, strconv.Itoa(n))
+			continue
+		}
+		codes = append(codes, c)
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("no status for the codes %s, use -status code=status", strings.Join(unknown, ", "))
+	}
+	return codes, nil
+}
+
+// Generate the source of the codes
+func generate(pkg, lang string, codes []*code, declared map[string]bool) ([]byte, error) {
+	qualifier := "respond."
+	if pkg == "respond" {
+		qualifier = ""
+	}
+	var (
+		b       bytes.Buffer
+		body    bytes.Buffer
+		useHTTP bool
+	)
+	status := func(status int) string {
+		if name, ok := httpStatuses[status]; ok {
+			useHTTP = true
+			return "http." + name
+		}
+		return strconv.Itoa(status)
+	}
+
+	fmt.Fprintf(&body, "// Codes of the error catalog\nconst (\n")
+	for _, c := range codes {
+		if !declared["Code"+c.Name] {
+			fmt.Fprintf(&body, "\t// %s\n\tCode%s = %d\n", comment(c.Message), c.Name, c.Code)
+		}
+	}
+	fmt.Fprintf(&body, ")\n\n")
+
+	var vars bytes.Buffer
+	for _, c := range codes {
+		if !declared["Err"+c.Name] {
+			fmt.Fprintf(&vars, "\tErr%s = %sRegisterError(%s, Code%s, %q, %q)\n",
+				c.Name, qualifier, status(c.Status), c.Name, c.Cat, c.Short)
+		}
+	}
+	if vars.Len() > 0 {
+		fmt.Fprintf(&body, "// Errors of the catalog\nvar (\n%s)\n\n", vars.String())
+	}
+
+	for _, c := range codes {
+		if declared[c.Name] {
+			continue
+		}
+		fmt.Fprintf(&body, "// %s responds with error %d, %s\n", c.Name, c.Code, comment(c.Message))
+		if qualifier == "" {
+			fmt.Fprintf(&body, "func (r *Respond) %s(args ...Args) error {\n", c.Name)
+		} else {
+			fmt.Fprintf(&body, "func %s(r *respond.Respond, args ...respond.Args) error {\n", c.Name)
+		}
+		if qualifier == "" {
+			// the helpers of the package follow the status profile
+			fmt.Fprintf(&body, "\treturn r.catalogError(%s, Code%s, args...)\n}\n\n", status(c.Status), c.Name)
+		} else {
+			fmt.Fprintf(&body, "\treturn r.Error(%s, Code%s, args...)\n}\n\n", status(c.Status), c.Name)
+		}
+	}
+
+	fmt.Fprintf(&b, "// Code generated by respond-gen from the %s catalog. DO NOT EDIT.\n\npackage %s\n\n", lang, pkg)
+	var imports []string
+	if useHTTP {
+		imports = append(imports, `"net/http"`)
+	}
+	if qualifier != "" {
+		imports = append(imports, `respond "`+respondImport+`"`)
+	}
+	if len(imports) > 0 {
+		fmt.Fprintf(&b, "import (\n\t%s\n)\n\n", strings.Join(imports, "\n\t"))
+	}
+	b.Write(body.Bytes())
+	return format.Source(b.Bytes())
+}
+
+// Get the names declared in the package of a directory and the name of
+// the package, the output file and the tests are left out
+func declaredNames(dir, output string) (map[string]bool, string, error) {
+	declared := map[string]bool{}
+	pkgs, err := parser.ParseDir(token.NewFileSet(), dir, func(info os.FileInfo) bool {
+		return info.Name() != output && !strings.HasSuffix(info.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return nil, "", err
+	}
+	name := ""
+	for pkgName, pkg := range pkgs {
+		name = pkgName
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				switch d := decl.(type) {
+				case *ast.FuncDecl:
+					declared[d.Name.Name] = true
+				case *ast.GenDecl:
+					for _, spec := range d.Specs {
+						switch s := spec.(type) {
+						case *ast.ValueSpec:
+							for _, n := range s.Names {
+								declared[n.Name] = true
+							}
+						case *ast.TypeSpec:
+							declared[s.Name.Name] = true
+						}
+					}
+				}
+			}
+		}
+	}
+	return declared, name, nil
+}
+
+// Convert a short name like token-expired to TokenExpired
+func identifier(short string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(short, func(c rune) bool {
+		return !unicode.IsLetter(c) && !unicode.IsDigit(c)
+	}) {
+		runes := []rune(part)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+	name := b.String()
+	if name != "" && !unicode.IsLetter([]rune(name)[0]) {
+		return ""
+	}
+	return name
+}
+
+func comment(message string) string {
+	return strings.Join(strings.Fields(message), " ")
+}
+
+func level(node interface{}) map[string]interface{} {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		return n
+	case map[string]map[string]interface{}:
+		l := make(map[string]interface{}, len(n))
+		for k, v := range n {
+			l[k] = v
+		}
+		return l
+	}
+	return nil
+}
+
+// Statuses of the codes of the built-in catalog
+var builtinStatuses = map[int]int{
+	1001: 446,
+	1002: 404,
+	1003: 400,
+	1004: 400,
+	1005: 400,
+	3001: 401,
+	3002: 500,
+	3003: 500,
+	3005: 401,
+	3006: 401,
+	3007: 401,
+	3008: 500,
+	3009: 500,
+	3010: 401,
+	3011: 401,
+	3012: 401,
+	3013: 401,
+	3014: 401,
+	3015: 401,
+	5401: 401,
+	5403: 403,
+	5404: 404,
+	5405: 405,
+	5406: 406,
+	5412: 412,
+	5420: 420,
+	5422: 401,
+	5445: 503,
+	5447: 447,
+	5448: 448,
+	5449: 449,
+	5500: 500,
+}
+
+// Names of the net/http constants of the statuses
+var httpStatuses = map[int]string{
+	400: "StatusBadRequest",
+	401: "StatusUnauthorized",
+	402: "StatusPaymentRequired",
+	403: "StatusForbidden",
+	404: "StatusNotFound",
+	405: "StatusMethodNotAllowed",
+	406: "StatusNotAcceptable",
+	408: "StatusRequestTimeout",
+	409: "StatusConflict",
+	410: "StatusGone",
+	412: "StatusPreconditionFailed",
+	413: "StatusRequestEntityTooLarge",
+	415: "StatusUnsupportedMediaType",
+	422: "StatusUnprocessableEntity",
+	423: "StatusLocked",
+	428: "StatusPreconditionRequired",
+	429: "StatusTooManyRequests",
+	500: "StatusInternalServerError",
+	501: "StatusNotImplemented",
+	502: "StatusBadGateway",
+	503: "StatusServiceUnavailable",
+	504: "StatusGatewayTimeout",
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	respond "github.com/mrjosh/respond.go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeneratedCodesAreUpToDate(t *testing.T) {
+
+	t.Parallel()
+
+	declared, pkg, err := declaredNames("../..", "codes_gen.go")
+	assert.NoError(t, err)
+	assert.Equal(t, "respond", pkg)
+
+	codes, err := catalogCodes(respond.NewMessages().Languages()["en"], statusFlag{})
+	assert.NoError(t, err)
+	source, err := generate(pkg, "en", codes, declared)
+	assert.NoError(t, err)
+
+	committed, err := ioutil.ReadFile("../../codes_gen.go")
+	assert.NoError(t, err)
+	assert.Equal(t, string(committed), string(source), "run go generate")
+}
+
+func TestRunPackage(t *testing.T) {
+
+	t.Parallel()
+
+	dir := t.TempDir()
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "api.go"), []byte("package api\n\nfunc NotFound() {}\n"), 0644))
+
+	var stderr bytes.Buffer
+	output := filepath.Join(dir, "codes_gen.go")
+	assert.Equal(t, 0, run([]string{"-o", output, "-status", "6001=429,6002=402", "testdata/en.json"}, &stderr))
+	assert.Empty(t, stderr.String())
+
+	source, err := ioutil.ReadFile(output)
+	assert.NoError(t, err)
+	for _, line := range []string{
+		"// Code generated by respond-gen from the en catalog. DO NOT EDIT.\n\npackage api\n",
+		"\trespond \"github.com/mrjosh/respond.go\"\n",
+		"\t// Quota of {plan} is exceeded\n\tCodeQuotaExceeded = 6001\n",
+		"\tCodeError6002 = 6002\n",
+		"\tErrQuotaExceeded = respond.RegisterError(http.StatusTooManyRequests, CodeQuotaExceeded, \"billing\", \"quota-exceeded\")\n",
+		"// Error6002 responds with error 6002, {count} seats are left\n" +
+			"func Error6002(r *respond.Respond, args ...respond.Args) error {\n" +
+			"\treturn r.Error(http.StatusPaymentRequired, CodeError6002, args...)\n}\n",
+	} {
+		assert.Contains(t, string(source), line)
+	}
+	assert.NotContains(t, string(source), "func NotFound(")
+}
+
+func TestRunWithoutStatus(t *testing.T) {
+
+	t.Parallel()
+
+	dir := t.TempDir()
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "api.go"), []byte("package api\n"), 0644))
+
+	var stderr bytes.Buffer
+	assert.Equal(t, 1, run([]string{"-o", filepath.Join(dir, "codes_gen.go"), "testdata/en.json"}, &stderr))
+	assert.Equal(t, "respond-gen: no status for the codes 6001, 6002, use -status code=status\n", stderr.String())
+}
+
+func TestIdentifier(t *testing.T) {
+
+	t.Parallel()
+
+	for short, name := range map[string]string{
+		"token-expired":           "TokenExpired",
+		"app_token.not generated": "AppTokenNotGenerated",
+		"":                        "",
+		"404-page":                "",
+	} {
+		assert.Equal(t, name, identifier(short), short)
+	}
+}
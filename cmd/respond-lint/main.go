@@ -0,0 +1,99 @@
+// Command respond-lint checks the message catalogs of respond against
+// the catalog of a reference language and the registered errors. It
+// reports missing, extra and empty messages, metadata and placeholder
+// mismatches, unregistered or untranslated error codes and generic
+// translations shared by different messages, and exits with status 1
+// when an issue is found
+//
+//      respond-lint [-ref en] [-ignore kind,...] [-builtin=false] [catalog dir or file ...]
+//
+// The catalogs of the arguments are added to the built-in en and fa
+// catalogs, a file like translations/de.json is the catalog of de
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	respond "github.com/mrjosh/respond.go"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// Run the linter and get the exit status, 0 when the catalogs are
+// clean, 1 when an issue is found and 2 when the catalogs can not be
+// loaded
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("respond-lint", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	var (
+		reference = flags.String("ref", respond.DefaultLanguage, "reference language of the catalogs")
+		ignore    = flags.String("ignore", "", "comma separated kinds of issues to ignore, like duplicate,unused-code")
+		builtin   = flags.Bool("builtin", true, "lint the built-in en and fa catalogs")
+	)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	catalogs := map[string]map[string]interface{}{}
+	if *builtin {
+		for lang, catalog := range respond.NewMessages().Languages() {
+			catalogs[lang] = catalog
+		}
+	}
+	for _, name := range flags.Args() {
+		if err := load(catalogs, name); err != nil {
+			fmt.Fprintln(stderr, err)
+			return 2
+		}
+	}
+
+	ignored := map[string]bool{}
+	for _, kind := range strings.Split(*ignore, ",") {
+		ignored[strings.TrimSpace(kind)] = true
+	}
+	count := 0
+	for _, issue := range respond.LintCatalogs(catalogs, *reference) {
+		if ignored[issue.Kind] {
+			continue
+		}
+		fmt.Fprintln(stdout, issue)
+		count++
+	}
+	if count > 0 {
+		fmt.Fprintf(stderr, "respond-lint: %d issues in %d languages\n", count, len(catalogs))
+		return 1
+	}
+	return 0
+}
+
+// Add the catalogs of a directory or a single catalog file
+func load(catalogs map[string]map[string]interface{}, name string) error {
+	info, err := os.Stat(name)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		catalog, err := respond.LoadCatalogFile(name)
+		if err != nil {
+			return err
+		}
+		base := filepath.Base(name)
+		catalogs[strings.TrimSuffix(base, filepath.Ext(base))] = catalog
+		return nil
+	}
+	loaded, err := respond.LoadCatalogDir(name)
+	if err != nil {
+		return err
+	}
+	for lang, catalog := range loaded {
+		catalogs[lang] = catalog
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunBuiltin(t *testing.T) {
+
+	t.Parallel()
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 0, run(nil, &stdout, &stderr))
+	assert.Empty(t, stdout.String())
+	assert.Empty(t, stderr.String())
+}
+
+func TestRunCatalogs(t *testing.T) {
+
+	t.Parallel()
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 1, run([]string{"../../testdata/catalogs"}, &stdout, &stderr))
+	assert.Contains(t, stdout.String(), "de: errors.1001.message: missing: key of en is missing\n")
+	assert.Contains(t, stderr.String(), "issues in 5 languages\n")
+
+	stdout.Reset()
+	assert.Equal(t, 0, run([]string{"-ignore", "missing", "../../testdata/catalogs/de.json"}, &stdout, &stderr))
+	assert.Empty(t, stdout.String())
+}
+
+func TestRunLoadError(t *testing.T) {
+
+	t.Parallel()
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 2, run([]string{"../../testdata/invalid"}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "it.json")
+}
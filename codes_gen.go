@@ -0,0 +1,171 @@
+// Code generated by respond-gen from the en catalog. DO NOT EDIT.
+
+package respond
+
+import (
+	"net/http"
+)
+
+// Codes of the error catalog
+const (
+	// Oops... Requested field {field} is not found!
+	CodeFieldNotFound = 1001
+	// Oops... Requested User does not exists!
+	CodeUserNotFound = 1002
+	// Oops... Client type is not entered!
+	CodeClientTypeMissing = 1003
+	// Failed because of duplicate {field}
+	CodeDuplicated = 1004
+	// Failed because of dablicated user role
+	CodeDuplicatedUserRole = 1005
+	// You are not logged on
+	CodeNotLoggedOn = 3001
+	// Application token did not generated successfully
+	CodeAppTokenNotGenerated = 3002
+	// User token did not generated successfully
+	CodeUserTokenNotGenerated = 3003
+	// Request token did not contains user information
+	CodeTokenWithoutUser = 3005
+	// Did not set request token
+	CodeTokenNotSet = 3006
+	// can not decode the token
+	CodeTokenDecodeFailed = 3007
+	// can not generate token for authentication
+	CodeAuthTokenNotGenerated = 3008
+	// can not create token
+	CodeTokenNotCreated = 3009
+	// Token expired!
+	CodeTokenExpired = 3010
+	// Token is invalid!
+	CodeTokenInvalid = 3011
+	// Token Blacklisted
+	CodeTokenBlacklisted = 3012
+	// Payload invalid!
+	CodePayloadInvalid = 3013
+	// Claim Invalid
+	CodeClaimInvalid = 3014
+	// An error occurred on token validation
+	CodeTokenValidationFailed = 3015
+	// Authentication unauthorized...
+	CodeUnauthorized = 5401
+	// You do not have permission to access the requested resource
+	CodeForbidden = 5403
+	// Oops... The requested page not found!
+	CodeNotFound = 5404
+	// Oops... The method you requested is not allowed!
+	CodeMethodNotAllowed = 5405
+	// Oops... The parameters you entered are wrong!
+	CodeWrongParameters = 5406
+	// Oops... The requested resource is changed by another request!
+	CodePreconditionFailed = 5412
+	// Validation Error
+	CodeValidationFailed = 5420
+	// Token is not valid
+	CodeTokenNotValid = 5422
+	// Oops... Database connection refused
+	CodeDatabaseConnectionRefused = 5445
+	// Oops... Delete action was not successfully executed
+	CodeDeleteFailed = 5447
+	// Oops... Insert action was not successfully executed
+	CodeInsertFailed = 5448
+	// Oops... Update action was not successfully executed
+	CodeUpdateFailed = 5449
+	// Oops... Something went wrong on our side!
+	CodeInternalServerError = 5500
+)
+
+// Errors of the catalog
+var (
+	ErrFieldNotFound             = RegisterError(446, CodeFieldNotFound, "", "field-not-found")
+	ErrUserNotFound              = RegisterError(http.StatusNotFound, CodeUserNotFound, "", "user-not-found")
+	ErrClientTypeMissing         = RegisterError(http.StatusBadRequest, CodeClientTypeMissing, "", "client-type-missing")
+	ErrDuplicated                = RegisterError(http.StatusBadRequest, CodeDuplicated, "", "duplicated")
+	ErrDuplicatedUserRole        = RegisterError(http.StatusBadRequest, CodeDuplicatedUserRole, "", "duplicated-user-role")
+	ErrNotLoggedOn               = RegisterError(http.StatusUnauthorized, CodeNotLoggedOn, "auth", "not-logged-on")
+	ErrAppTokenNotGenerated      = RegisterError(http.StatusInternalServerError, CodeAppTokenNotGenerated, "auth", "app-token-not-generated")
+	ErrUserTokenNotGenerated     = RegisterError(http.StatusInternalServerError, CodeUserTokenNotGenerated, "auth", "user-token-not-generated")
+	ErrTokenWithoutUser          = RegisterError(http.StatusUnauthorized, CodeTokenWithoutUser, "auth", "token-without-user")
+	ErrTokenNotSet               = RegisterError(http.StatusUnauthorized, CodeTokenNotSet, "auth", "token-not-set")
+	ErrTokenDecodeFailed         = RegisterError(http.StatusUnauthorized, CodeTokenDecodeFailed, "auth", "token-decode-failed")
+	ErrAuthTokenNotGenerated     = RegisterError(http.StatusInternalServerError, CodeAuthTokenNotGenerated, "auth", "auth-token-not-generated")
+	ErrTokenNotCreated           = RegisterError(http.StatusInternalServerError, CodeTokenNotCreated, "auth", "token-not-created")
+	ErrTokenExpired              = RegisterError(http.StatusUnauthorized, CodeTokenExpired, "auth", "token-expired")
+	ErrTokenInvalid              = RegisterError(http.StatusUnauthorized, CodeTokenInvalid, "auth", "token-invalid")
+	ErrTokenBlacklisted          = RegisterError(http.StatusUnauthorized, CodeTokenBlacklisted, "auth", "token-blacklisted")
+	ErrPayloadInvalid            = RegisterError(http.StatusUnauthorized, CodePayloadInvalid, "auth", "payload-invalid")
+	ErrClaimInvalid              = RegisterError(http.StatusUnauthorized, CodeClaimInvalid, "auth", "claim-invalid")
+	ErrTokenValidationFailed     = RegisterError(http.StatusUnauthorized, CodeTokenValidationFailed, "auth", "token-validation-failed")
+	ErrUnauthorized              = RegisterError(http.StatusUnauthorized, CodeUnauthorized, "", "unauthorized")
+	ErrForbidden                 = RegisterError(http.StatusForbidden, CodeForbidden, "", "forbidden")
+	ErrNotFound                  = RegisterError(http.StatusNotFound, CodeNotFound, "", "not-found")
+	ErrMethodNotAllowed          = RegisterError(http.StatusMethodNotAllowed, CodeMethodNotAllowed, "", "method-not-allowed")
+	ErrWrongParameters           = RegisterError(http.StatusNotAcceptable, CodeWrongParameters, "", "wrong-parameters")
+	ErrPreconditionFailed        = RegisterError(http.StatusPreconditionFailed, CodePreconditionFailed, "", "precondition-failed")
+	ErrValidationFailed          = RegisterError(420, CodeValidationFailed, "", "validation-failed")
+	ErrTokenNotValid             = RegisterError(http.StatusUnauthorized, CodeTokenNotValid, "", "token-not-valid")
+	ErrDatabaseConnectionRefused = RegisterError(http.StatusServiceUnavailable, CodeDatabaseConnectionRefused, "", "database-connection-refused")
+	ErrDeleteFailed              = RegisterError(447, CodeDeleteFailed, "", "delete-failed")
+	ErrInsertFailed              = RegisterError(448, CodeInsertFailed, "", "insert-failed")
+	ErrUpdateFailed              = RegisterError(449, CodeUpdateFailed, "", "update-failed")
+	ErrInternalServerError       = RegisterError(http.StatusInternalServerError, CodeInternalServerError, "", "internal-server-error")
+)
+
+// FieldNotFound responds with error 1001, Oops... Requested field {field} is not found!
+func (r *Respond) FieldNotFound(args ...Args) error {
+	return r.catalogError(446, CodeFieldNotFound, args...)
+}
+
+// UserNotFound responds with error 1002, Oops... Requested User does not exists!
+func (r *Respond) UserNotFound(args ...Args) error {
+	return r.catalogError(http.StatusNotFound, CodeUserNotFound, args...)
+}
+
+// ClientTypeMissing responds with error 1003, Oops... Client type is not entered!
+func (r *Respond) ClientTypeMissing(args ...Args) error {
+	return r.catalogError(http.StatusBadRequest, CodeClientTypeMissing, args...)
+}
+
+// Duplicated responds with error 1004, Failed because of duplicate {field}
+func (r *Respond) Duplicated(args ...Args) error {
+	return r.catalogError(http.StatusBadRequest, CodeDuplicated, args...)
+}
+
+// DuplicatedUserRole responds with error 1005, Failed because of dablicated user role
+func (r *Respond) DuplicatedUserRole(args ...Args) error {
+	return r.catalogError(http.StatusBadRequest, CodeDuplicatedUserRole, args...)
+}
+
+// AppTokenNotGenerated responds with error 3002, Application token did not generated successfully
+func (r *Respond) AppTokenNotGenerated(args ...Args) error {
+	return r.catalogError(http.StatusInternalServerError, CodeAppTokenNotGenerated, args...)
+}
+
+// UserTokenNotGenerated responds with error 3003, User token did not generated successfully
+func (r *Respond) UserTokenNotGenerated(args ...Args) error {
+	return r.catalogError(http.StatusInternalServerError, CodeUserTokenNotGenerated, args...)
+}
+
+// AuthTokenNotGenerated responds with error 3008, can not generate token for authentication
+func (r *Respond) AuthTokenNotGenerated(args ...Args) error {
+	return r.catalogError(http.StatusInternalServerError, CodeAuthTokenNotGenerated, args...)
+}
+
+// TokenNotCreated responds with error 3009, can not create token
+func (r *Respond) TokenNotCreated(args ...Args) error {
+	return r.catalogError(http.StatusInternalServerError, CodeTokenNotCreated, args...)
+}
+
+// PreconditionFailed responds with error 5412, Oops... The requested resource is changed by another request!
+func (r *Respond) PreconditionFailed(args ...Args) error {
+	return r.catalogError(http.StatusPreconditionFailed, CodePreconditionFailed, args...)
+}
+
+// ValidationFailed responds with error 5420, Validation Error
+func (r *Respond) ValidationFailed(args ...Args) error {
+	return r.catalogError(420, CodeValidationFailed, args...)
+}
+
+// DatabaseConnectionRefused responds with error 5445, Oops... Database connection refused
+func (r *Respond) DatabaseConnectionRefused(args ...Args) error {
+	return r.catalogError(http.StatusServiceUnavailable, CodeDatabaseConnectionRefused, args...)
+}
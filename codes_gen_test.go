@@ -0,0 +1,39 @@
+package respond
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeneratedHelpers(t *testing.T) {
+
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+	assert.NoError(t, NewWithWriter(recorder).TokenExpired())
+
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	assert.JSONEq(t, `{"status":"failed","error":3010,"message":"Token expired!"}`, recorder.Body.String())
+
+	recorder = httptest.NewRecorder()
+	assert.NoError(t, NewWithWriter(recorder).FieldNotFound(Args{"field": "email"}))
+
+	assert.Equal(t, 446, recorder.Code)
+	assert.JSONEq(t, `{"status":"failed","error":1001,"message":"Oops... Requested field email is not found!"}`, recorder.Body.String())
+}
+
+func TestGeneratedCodesMatchRegistry(t *testing.T) {
+
+	t.Parallel()
+
+	for code, e := range map[int]*Error{
+		CodeFieldNotFound:       ErrFieldNotFound,
+		CodeTokenExpired:        ErrTokenExpired,
+		CodeInternalServerError: ErrInternalServerError,
+	} {
+		assert.Equal(t, e.Code, code)
+	}
+}
@@ -0,0 +1,163 @@
+package respond
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// How the ETag of successful responses is generated
+type ETagMode int
+
+const (
+	// No ETag is generated
+	ETagNone ETagMode = iota
+
+	// A strong ETag is generated from the encoded body
+	ETagStrong
+
+	// A weak ETag is generated from the encoded body
+	ETagWeak
+)
+
+// Set the ETag of the response, like "v42" or W/"v42". The value is
+// quoted when it is not, and it replaces a generated ETag
+//
+// @param etag string
+// @return *Respond
+func (r *Respond) SetETag(etag string) *Respond {
+	if etag != "" && !strings.HasSuffix(etag, `"`) {
+		etag = `"` + etag + `"`
+	}
+	r.etag = etag
+	return r
+}
+
+// Set the Last-Modified time of the response
+//
+// @param modified time.Time
+// @return *Respond
+func (r *Respond) SetLastModified(modified time.Time) *Respond {
+	r.lastModified = modified
+	return r
+}
+
+// Set whether the resource exists, it does unless it is set otherwise.
+// If-Match: * matches every existing resource and no missing one, like
+// RFC 9110 section 13.1.1 asks
+//
+//      if err := r.SetExists(user != nil).CheckPreconditions(); err != nil {
+//        return err
+//      }
+//
+// @param exists bool
+// @return *Respond
+func (r *Respond) SetExists(exists bool) *Respond {
+	r.missing = !exists
+	return r
+}
+
+// Generate the ETag of successful responses from their encoded body
+//
+//      r.GenerateETag(respond.ETagWeak).Succeed(users)
+//
+// @param mode ETagMode
+// @return *Respond
+func (r *Respond) GenerateETag(mode ETagMode) *Respond {
+	r.etagMode = mode
+	return r
+}
+
+// Check the If-Match and If-Unmodified-Since preconditions of the
+// request against the current ETag and Last-Modified of the resource.
+// When they fail a 412 error is written and ErrPreconditionFailed is
+// returned, so the resource is left unchanged
+//
+//      if err := r.SetETag(user.Version).CheckPreconditions(); err != nil {
+//        return err
+//      }
+//      // update the user
+//      return r.SetETag(updated.Version).UpdateSucceeded()
+//
+// @return error
+func (r *Respond) CheckPreconditions() error {
+	if r.request == nil || r.preconditionsMet() {
+		return nil
+	}
+	if err := r.Err(ErrPreconditionFailed); err != nil {
+		return err
+	}
+	return ErrPreconditionFailed
+}
+
+func (r *Respond) preconditionsMet() bool {
+	if ifMatch := r.request.Header.Get("If-Match"); ifMatch != "" {
+		return matchETag(ifMatch, r.etag, !r.missing, false)
+	}
+	since, err := http.ParseTime(r.request.Header.Get("If-Unmodified-Since"))
+	if err != nil || r.lastModified.IsZero() {
+		return true
+	}
+	return !r.lastModified.Truncate(time.Second).After(since)
+}
+
+// Set the validators of a successful response and report whether the
+// representation of the client is still fresh
+func (r *Respond) notModified(body []byte) bool {
+	if r.statusCode < 200 || r.statusCode > 299 {
+		return false
+	}
+	etag := r.etag
+	if etag == "" && r.etagMode != ETagNone {
+		sum := sha256.Sum256(body)
+		etag = `"` + hex.EncodeToString(sum[:16]) + `"`
+		if r.etagMode == ETagWeak {
+			etag = "W/" + etag
+		}
+	}
+	header := r.writer.Header()
+	if etag != "" {
+		header.Set("ETag", etag)
+	}
+	if !r.lastModified.IsZero() {
+		header.Set("Last-Modified", r.lastModified.UTC().Format(http.TimeFormat))
+	}
+	if r.request == nil || r.request.Method != http.MethodGet && r.request.Method != http.MethodHead {
+		return false
+	}
+	if ifNoneMatch := r.request.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		return matchETag(ifNoneMatch, etag, !r.missing, true)
+	}
+	since, err := http.ParseTime(r.request.Header.Get("If-Modified-Since"))
+	if err != nil || r.lastModified.IsZero() {
+		return false
+	}
+	return !r.lastModified.Truncate(time.Second).After(since)
+}
+
+// Whether an ETag is in the list of an If-Match or If-None-Match
+// header, weak comparison ignores the W/ prefix. The * list matches any
+// existing resource, with or without an ETag
+func matchETag(list, etag string, exists, weak bool) bool {
+	if strings.TrimSpace(list) == "*" {
+		return exists
+	}
+	if etag == "" || !weak && strings.HasPrefix(etag, "W/") {
+		return false
+	}
+	for _, candidate := range strings.Split(list, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if weak {
+			if strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+			continue
+		}
+		if candidate == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,179 @@
+package respond
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateETag(t *testing.T) {
+
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+	assert.NoError(t, NewWithRequest(recorder, req).GenerateETag(ETagStrong).Succeed([]string{"a"}))
+
+	etag := recorder.Header().Get("ETag")
+	assert.Regexp(t, `^"[0-9a-f]{32}"$`, etag)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	for _, ifNoneMatch := range []string{etag, `"other", ` + etag, "W/" + etag, "*"} {
+		recorder = httptest.NewRecorder()
+		req = httptest.NewRequest(http.MethodGet, "/users", nil)
+		req.Header.Set("If-None-Match", ifNoneMatch)
+		assert.NoError(t, NewWithRequest(recorder, req).GenerateETag(ETagStrong).Succeed([]string{"a"}))
+
+		assert.Equal(t, http.StatusNotModified, recorder.Code, ifNoneMatch)
+		assert.Equal(t, etag, recorder.Header().Get("ETag"))
+		assert.Empty(t, recorder.Body.String())
+		assert.Empty(t, recorder.Header().Get("Content-Type"))
+	}
+
+	recorder = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/users", nil)
+	req.Header.Set("If-None-Match", etag)
+	assert.NoError(t, NewWithRequest(recorder, req).GenerateETag(ETagWeak).Succeed([]string{"b"}))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Regexp(t, `^W/"[0-9a-f]{32}"$`, recorder.Header().Get("ETag"))
+	assert.NotEmpty(t, recorder.Body.String())
+}
+
+func TestSetETag(t *testing.T) {
+
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	req.Header.Set("If-None-Match", `"v41"`)
+	assert.NoError(t, NewWithRequest(recorder, req).GenerateETag(ETagStrong).SetETag("v42").Succeed("user"))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, `"v42"`, recorder.Header().Get("ETag"))
+
+	recorder = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodHead, "/users/1", nil)
+	req.Header.Set("If-None-Match", `W/"v42"`)
+	assert.NoError(t, NewWithRequest(recorder, req).SetETag(`W/"v42"`).Succeed("user"))
+
+	assert.Equal(t, http.StatusNotModified, recorder.Code)
+}
+
+func TestLastModified(t *testing.T) {
+
+	t.Parallel()
+
+	modified := time.Date(2026, 10, 18, 10, 30, 15, 500, time.UTC)
+
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+	req.Header.Set("If-Modified-Since", "Sun, 18 Oct 2026 10:30:15 GMT")
+	assert.NoError(t, NewWithRequest(recorder, req).SetLastModified(modified).Succeed("users"))
+
+	assert.Equal(t, http.StatusNotModified, recorder.Code)
+	assert.Equal(t, "Sun, 18 Oct 2026 10:30:15 GMT", recorder.Header().Get("Last-Modified"))
+
+	recorder = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/users", nil)
+	req.Header.Set("If-Modified-Since", "Sun, 18 Oct 2026 10:30:14 GMT")
+	assert.NoError(t, NewWithRequest(recorder, req).SetLastModified(modified).Succeed("users"))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestConditionalIgnoresErrorsAndWrites(t *testing.T) {
+
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+	req.Header.Set("If-None-Match", "*")
+	assert.NoError(t, NewWithRequest(recorder, req).SetETag("v1").NotFound())
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	assert.Empty(t, recorder.Header().Get("ETag"))
+
+	recorder = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPut, "/users/1", nil)
+	req.Header.Set("If-None-Match", `"v2"`)
+	assert.NoError(t, NewWithRequest(recorder, req).SetETag("v2").UpdateSucceeded())
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, `"v2"`, recorder.Header().Get("ETag"))
+}
+
+func TestCheckPreconditionsAnyETag(t *testing.T) {
+
+	t.Parallel()
+
+	// the resource exists without an ETag
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/users/1", nil)
+	req.Header.Set("If-Match", "*")
+	r := NewWithRequest(recorder, req)
+	assert.NoError(t, r.CheckPreconditions())
+	assert.False(t, r.Written())
+
+	recorder = httptest.NewRecorder()
+	r = NewWithRequest(recorder, req).SetExists(false)
+	assert.True(t, errors.Is(r.CheckPreconditions(), ErrPreconditionFailed))
+	assert.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+}
+
+func TestCheckPreconditions(t *testing.T) {
+
+	t.Parallel()
+
+	for header, value := range map[string]string{
+		"If-Match":            `"v1", "v2"`,
+		"If-Unmodified-Since": "Sun, 18 Oct 2026 10:30:15 GMT",
+	} {
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPut, "/users/1", nil)
+		req.Header.Set(header, value)
+		r := NewWithRequest(recorder, req).SetETag("v2").SetLastModified(time.Date(2026, 10, 18, 10, 30, 15, 0, time.UTC))
+
+		assert.NoError(t, r.CheckPreconditions(), header)
+		assert.False(t, r.Written())
+	}
+
+	for header, value := range map[string]string{
+		"If-Match":            `"v1", W/"v3"`,
+		"If-Unmodified-Since": "Sun, 18 Oct 2026 10:30:14 GMT",
+	} {
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPut, "/users/1", nil)
+		req.Header.Set(header, value)
+		r := NewWithRequest(recorder, req).SetETag(`W/"v3"`).SetLastModified(time.Date(2026, 10, 18, 10, 30, 15, 0, time.UTC))
+
+		err := r.CheckPreconditions()
+		assert.True(t, errors.Is(err, ErrPreconditionFailed), header)
+		assert.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+		assert.JSONEq(t, `{"status":"failed","error":5412,"message":"Oops... The requested resource is changed by another request!"}`, recorder.Body.String())
+	}
+}
+
+func TestMiddlewareETag(t *testing.T) {
+
+	t.Parallel()
+
+	handler := Middleware(Options{ETag: ETagWeak})(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		From(req).Succeed("users")
+	}))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	etag := recorder.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+
+	recorder = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("If-None-Match", etag)
+	handler.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusNotModified, recorder.Code)
+}
@@ -0,0 +1,303 @@
+package respond
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Config holds the settings shared by the responses it creates, the
+// responses can change their own settings without changing the config
+//
+//	config := respond.New(
+//	  respond.WithLanguage("fa"),
+//	  respond.WithStatusProfile(respond.StandardStatuses),
+//	)
+//	http.HandleFunc("/users", func(w http.ResponseWriter, req *http.Request) {
+//	  config.Request(w, req).Succeed(users)
+//	})
+type Config struct {
+	defaultLanguage string
+	messages        *Messages
+	mode            Mode
+	encoders        []registeredEncoder
+	encodersVersion int
+	envelope        Envelope
+	statuses        StatusProfile
+	challenge       Challenge
+	etagMode        ETagMode
+	hooks           Hooks
+	logger          Logger
+	requestIDHeader string
+	requestID       func() string
+}
+
+// Option sets a setting of a Config
+type Option func(c *Config)
+
+// Hooks are called by the responses of a Config
+type Hooks struct {
+	// OnResponse is called after a response is written with its status
+	OnResponse func(r *Respond, statusCode int)
+
+	// OnError is called with the errors of writing responses, like the
+	// errors of encoders and of the connection
+	OnError func(r *Respond, err error)
+
+	// OnMissing is called every time a key is missing from the whole
+	// fallback chain of the catalog
+	OnMissing func(lang, key string)
+}
+
+// Create a config of responses, the settings of the package are used
+// for the options which are not given
+//
+// @param opts ...Option
+// @return *Config
+func New(opts ...Option) *Config {
+	c := &Config{
+		defaultLanguage: DefaultLanguage,
+		mode:            DefaultMode,
+		envelope:        DefaultEnvelope,
+		requestIDHeader: "X-Request-ID",
+		requestID:       newRequestID,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.messages == nil {
+		c.messages = NewMessages()
+	}
+	return c
+}
+
+// Set the language of responses whose request accepts none of the
+// translations
+//
+// @param lang string
+// @return Option
+func WithLanguage(lang string) Option {
+	return func(c *Config) {
+		c.defaultLanguage = lang
+	}
+}
+
+// Set the catalog shared by the responses
+//
+// @param messages *Messages
+// @return Option
+func WithMessages(messages *Messages) Option {
+	return func(c *Config) {
+		c.messages = messages
+	}
+}
+
+// Set the output mode of error responses, ModeDefault keeps the mode of
+// the config
+//
+// @param mode Mode
+// @return Option
+func WithMode(mode Mode) Option {
+	return func(c *Config) {
+		if mode != ModeDefault {
+			c.mode = mode
+		}
+	}
+}
+
+// Add an encoder for a media type to the config only, the encoders
+// registered with RegisterEncoder up to now are used with it and the
+// encoder of an already registered media type is replaced
+//
+// @param mediaType string, encoder Encoder
+// @return Option
+func WithEncoder(mediaType string, encoder Encoder) Option {
+	mediaType = strings.ToLower(mediaType)
+	return func(c *Config) {
+		if c.encoders == nil {
+			encoders.RLock()
+			c.encoders = append([]registeredEncoder(nil), encoders.list...)
+			encoders.RUnlock()
+		} else {
+			c.encoders = append([]registeredEncoder(nil), c.encoders...)
+		}
+		c.encodersVersion = nextEncodersVersion()
+		for i, registered := range c.encoders {
+			if registered.mediaType == mediaType {
+				c.encoders[i].encoder = encoder
+				return
+			}
+		}
+		c.encoders = append(c.encoders, registeredEncoder{mediaType: mediaType, encoder: encoder})
+	}
+}
+
+// Set the envelope of the response bodies
+//
+// @param envelope Envelope
+// @return Option
+func WithEnvelope(envelope Envelope) Option {
+	return func(c *Config) {
+		c.envelope = envelope
+	}
+}
+
+// Set the status profile of the errors
+//
+// @param profile StatusProfile
+// @return Option
+func WithStatusProfile(profile StatusProfile) Option {
+	return func(c *Config) {
+		c.statuses = profile
+	}
+}
+
+// Set the WWW-Authenticate challenge of the auth errors
+//
+// @param challenge Challenge
+// @return Option
+func WithChallenge(challenge Challenge) Option {
+	return func(c *Config) {
+		c.challenge = challenge
+	}
+}
+
+// Set how the ETag of successful responses is generated
+//
+// @param mode ETagMode
+// @return Option
+func WithETag(mode ETagMode) Option {
+	return func(c *Config) {
+		c.etagMode = mode
+	}
+}
+
+// Set the hooks of the responses
+//
+// @param hooks Hooks
+// @return Option
+func WithHooks(hooks Hooks) Option {
+	return func(c *Config) {
+		c.hooks = hooks
+	}
+}
+
+// Set the logger of the errors of writing responses, nothing is logged
+// without one
+//
+// @param logger Logger
+// @return Option
+func WithLogger(logger Logger) Option {
+	return func(c *Config) {
+		c.logger = logger
+	}
+}
+
+// Set the header of request IDs and their generator for the middleware,
+// an empty header or a nil generator keeps the current one
+//
+// @param header string, generate func() string
+// @return Option
+func WithRequestID(header string, generate func() string) Option {
+	return func(c *Config) {
+		if header != "" {
+			c.requestIDHeader = header
+		}
+		if generate != nil {
+			c.requestID = generate
+		}
+	}
+}
+
+// Derive a config with some settings changed, the config itself is not
+// changed
+//
+//	admin := config.With(respond.WithMode(respond.ModeProblem))
+//
+// @param opts ...Option
+// @return *Config
+func (c *Config) With(opts ...Option) *Config {
+	derived := *c
+	for _, opt := range opts {
+		opt(&derived)
+	}
+	return &derived
+}
+
+// Get the catalog shared by the responses of the config
+//
+// @return *Messages
+func (c *Config) Messages() *Messages {
+	return c.messages
+}
+
+// New respond type with custom writer in the default language of the
+// config
+//
+// @param w http.ResponseWriter
+// @return *Respond
+func (c *Config) Writer(w http.ResponseWriter) *Respond {
+	return &Respond{
+		writer:          w,
+		messages:        c.messages,
+		lang:            c.defaultLanguage,
+		mode:            c.mode,
+		encoders:        c.encoders,
+		encodersVersion: c.encodersVersion,
+		challenge:       c.challenge,
+		etagMode:        c.etagMode,
+		envelope:        c.envelope,
+		statuses:        c.statuses,
+		hooks:           c.hooks,
+		logger:          c.logger,
+	}
+}
+
+// New respond type with custom writer which negotiates the language of
+// responses from the Accept-Language header of the request, with the
+// default language of the config as the fallback
+//
+// @param w http.ResponseWriter, req *http.Request
+// @return *Respond
+func (c *Config) Request(w http.ResponseWriter, req *http.Request) *Respond {
+	r := c.Writer(w)
+	r.request = req
+	r.lang = negotiateLanguage(
+		req.Header.Get("Accept-Language"),
+		c.messages.catalog().supported,
+		c.defaultLanguage,
+	)
+	return r
+}
+
+// Middleware builds a Respond of the config for every request like the
+// Middleware function does
+//
+// @return func(http.Handler) http.Handler
+func (c *Config) Middleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			requestID := req.Header.Get(c.requestIDHeader)
+			if requestID == "" {
+				requestID = c.requestID()
+			}
+			w.Header().Set(c.requestIDHeader, requestID)
+
+			r := c.Request(w, req)
+			r.requestID = requestID
+			req = req.WithContext(NewContext(req.Context(), r))
+			r.request = req
+			r.negotiate()
+			next.ServeHTTP(w, req)
+		})
+	}
+}
+
+// Report an error of writing a response to the logger and the hooks
+func (r *Respond) report(err error) {
+	if r.logger != nil {
+		r.logger.Printf("respond: can not write response (request %q): %v", r.requestID, err)
+	}
+	if r.hooks.OnError != nil {
+		r.hooks.OnError(r, err)
+	}
+}
@@ -0,0 +1,164 @@
+package respond
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingLogger struct {
+	lines []string
+}
+
+func (l *recordingLogger) Printf(format string, v ...interface{}) {
+	l.lines = append(l.lines, fmt.Sprintf(format, v...))
+}
+
+func TestConfig(t *testing.T) {
+
+	t.Parallel()
+
+	config := New(
+		WithLanguage("fa"),
+		WithStatusProfile(StandardStatuses),
+		WithEnvelope(guidelineEnvelope),
+	)
+
+	recorder := httptest.NewRecorder()
+	assert.NoError(t, config.Writer(recorder).RequestFieldNotfound(Args{"field": "name"}))
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	assert.JSONEq(t, `{"success":false,"error":{"code":1001,"message":".فیلد name درخواست شده پیدا نشده است"}}`, recorder.Body.String())
+
+	// the language of the request is negotiated with the default
+	// language of the config as the fallback
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("Accept-Language", "en-US")
+	recorder = httptest.NewRecorder()
+	assert.NoError(t, config.Request(recorder, request).Succeed(nil))
+	assert.JSONEq(t, `{"success":true}`, recorder.Body.String())
+
+	request.Header.Set("Accept-Language", "de")
+	recorder = httptest.NewRecorder()
+	assert.NoError(t, config.Request(recorder, request).NotFound())
+	assert.Contains(t, recorder.Body.String(), "صفحه")
+}
+
+func TestConfigLocalOverrides(t *testing.T) {
+
+	t.Parallel()
+
+	config := New(WithStatusProfile(StandardStatuses))
+
+	// the settings of a response are its own
+	recorder := httptest.NewRecorder()
+	assert.NoError(t, config.Writer(recorder).SetStatusProfile(LegacyStatuses).SetEnvelope(guidelineEnvelope).ValidationErrors(nil))
+	assert.Equal(t, 420, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	assert.NoError(t, config.Writer(recorder).ValidationErrors(nil))
+	assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"status":"failed"`)
+
+	// so are the translations of its catalog
+	r := config.Writer(httptest.NewRecorder())
+	r.Messages().AddLanguageTranslation("de", map[string]interface{}{"success": "erfolg"})
+	assert.NotContains(t, config.Messages().SupportedLanguages(), "de")
+
+	// derived configs leave the config unchanged
+	problems := config.With(WithMode(ModeProblem))
+	recorder = httptest.NewRecorder()
+	assert.NoError(t, problems.Writer(recorder).NotFound())
+	assert.Equal(t, ProblemContentType+"; charset=utf-8", recorder.Header().Get("Content-Type"))
+
+	recorder = httptest.NewRecorder()
+	assert.NoError(t, config.Writer(recorder).NotFound())
+	assert.Equal(t, "application/json; charset=utf-8", recorder.Header().Get("Content-Type"))
+}
+
+func TestConfigEncoders(t *testing.T) {
+
+	t.Parallel()
+
+	text := EncoderFunc(func(w io.Writer, v interface{}) error {
+		_, err := fmt.Fprint(w, v)
+		return err
+	})
+	config := New(WithEncoder("text/plain", text))
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("Accept", "text/plain")
+	recorder := httptest.NewRecorder()
+	assert.NoError(t, config.Request(recorder, request).Succeed(1))
+	assert.Equal(t, "text/plain; charset=utf-8", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, "map[result:1 status:success]", recorder.Body.String())
+
+	// the encoders of the config are not registered
+	recorder = httptest.NewRecorder()
+	assert.ErrorIs(t, NewWithRequest(recorder, request).Succeed(1), ErrNotAcceptable)
+	assert.Equal(t, http.StatusNotAcceptable, recorder.Code)
+
+	// the registered encoders are still used
+	request.Header.Set("Accept", "application/yaml")
+	recorder = httptest.NewRecorder()
+	assert.NoError(t, config.Request(recorder, request).Succeed(1))
+	assert.Equal(t, "application/yaml; charset=utf-8", recorder.Header().Get("Content-Type"))
+}
+
+func TestConfigHooks(t *testing.T) {
+
+	t.Parallel()
+
+	var statuses []int
+	var failures []error
+	var missing []string
+	logger := &recordingLogger{}
+	failing := EncoderFunc(func(w io.Writer, v interface{}) error {
+		return errors.New("broken")
+	})
+	config := New(
+		WithLogger(logger),
+		WithEncoder("application/broken", failing),
+		WithHooks(Hooks{
+			OnResponse: func(r *Respond, statusCode int) { statuses = append(statuses, statusCode) },
+			OnError:    func(r *Respond, err error) { failures = append(failures, err) },
+			OnMissing:  func(lang, key string) { missing = append(missing, lang+":"+key) },
+		}),
+	)
+
+	assert.NoError(t, config.Writer(httptest.NewRecorder()).NotFound())
+	assert.NoError(t, config.Writer(httptest.NewRecorder()).Succeed(nil))
+	assert.Equal(t, []int{http.StatusNotFound, http.StatusOK}, statuses)
+
+	assert.NoError(t, config.Writer(httptest.NewRecorder()).Error(http.StatusTeapot, 9999))
+	assert.Equal(t, []string{"en:errors.9999.message"}, missing)
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("Accept", "application/broken")
+	var encodeErr *EncodeError
+	assert.ErrorAs(t, config.Request(httptest.NewRecorder(), request).Succeed(nil), &encodeErr)
+	assert.Len(t, failures, 1)
+	assert.Len(t, logger.lines, 1)
+	assert.True(t, strings.HasPrefix(logger.lines[0], "respond: can not write response"))
+}
+
+func TestConfigMiddleware(t *testing.T) {
+
+	t.Parallel()
+
+	config := New(WithRequestID("X-Trace-ID", func() string { return "trace" }), WithStatusProfile(StandardStatuses))
+	handler := config.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "trace", From(r).RequestID())
+		From(r).ValidationErrors(nil)
+	}))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, "trace", recorder.Header().Get("X-Trace-ID"))
+	assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+}
@@ -0,0 +1,263 @@
+package respond
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Encoder serialises response bodies of a media type
+type Encoder interface {
+	Encode(w io.Writer, v interface{}) error
+}
+
+// EncoderFunc is an adapter to use ordinary functions as encoders
+type EncoderFunc func(w io.Writer, v interface{}) error
+
+func (f EncoderFunc) Encode(w io.Writer, v interface{}) error {
+	return f(w, v)
+}
+
+// Media types of the built-in encoders
+const (
+	MediaTypeJSON        = "application/json"
+	MediaTypeXML         = "application/xml"
+	MediaTypeYAML        = "application/yaml"
+	MediaTypeMessagePack = "application/msgpack"
+	MediaTypeCBOR        = "application/cbor"
+)
+
+type registeredEncoder struct {
+	mediaType string
+	encoder   Encoder
+}
+
+var encoders = struct {
+	list []registeredEncoder
+
+	// version of the list, it is changed by every registration
+	version int
+
+	// last is the last version given to a list of encoders, the global
+	// list and the lists of configs never share a version
+	last int
+	sync.RWMutex
+}{}
+
+func init() {
+	RegisterEncoder(MediaTypeJSON, JSONEncoder)
+	RegisterEncoder(MediaTypeXML, XMLEncoder)
+	RegisterEncoder("text/xml", XMLEncoder)
+	RegisterEncoder(MediaTypeYAML, YAMLEncoder)
+	RegisterEncoder("application/x-yaml", YAMLEncoder)
+	RegisterEncoder("text/yaml", YAMLEncoder)
+	RegisterEncoder(MediaTypeMessagePack, MessagePackEncoder)
+	RegisterEncoder("application/x-msgpack", MessagePackEncoder)
+	RegisterEncoder("application/vnd.msgpack", MessagePackEncoder)
+	RegisterEncoder(MediaTypeCBOR, CBOREncoder)
+}
+
+// Register an encoder for a media type, the encoder of an already
+// registered media type is replaced. The first registered encoder is
+// used when the request accepts any media type
+//
+// @param mediaType string, encoder Encoder
+func RegisterEncoder(mediaType string, encoder Encoder) {
+	mediaType = strings.ToLower(mediaType)
+	encoders.Lock()
+	defer encoders.Unlock()
+	encoders.last++
+	encoders.version = encoders.last
+	for i, registered := range encoders.list {
+		if registered.mediaType == mediaType {
+			encoders.list[i].encoder = encoder
+			return
+		}
+	}
+	encoders.list = append(encoders.list, registeredEncoder{mediaType: mediaType, encoder: encoder})
+}
+
+// Pick the encoder for the value of an Accept header
+//
+// Media ranges are ordered by their q-values and then by how specific
+// they are, structured syntax suffixes like application/problem+json
+// match the encoder of application/json. The first registered encoder
+// is used when the header is empty
+//
+// @param accept string
+// @return (mediaType string, encoder Encoder, ok bool)
+func NegotiateEncoder(accept string) (string, Encoder, bool) {
+	encoders.RLock()
+	defer encoders.RUnlock()
+	return negotiateEncoder(encoders.list, accept)
+}
+
+// Get the version of the registered encoders
+func encodersVersion() int {
+	encoders.RLock()
+	defer encoders.RUnlock()
+	return encoders.version
+}
+
+// Get a new version for a list of encoders of a config
+func nextEncodersVersion() int {
+	encoders.Lock()
+	defer encoders.Unlock()
+	encoders.last++
+	return encoders.last
+}
+
+// Pick the encoder for the value of an Accept header from a list of
+// encoders
+func negotiateEncoder(list []registeredEncoder, accept string) (string, Encoder, bool) {
+	if len(list) == 0 {
+		return "", nil, false
+	}
+	if strings.TrimSpace(accept) == "" {
+		return list[0].mediaType, list[0].encoder, true
+	}
+	ranges := parseQualityValues(accept)
+	sort.SliceStable(ranges, func(i, j int) bool {
+		if ranges[i].q != ranges[j].q {
+			return ranges[i].q > ranges[j].q
+		}
+		return specificity(ranges[i].tag) > specificity(ranges[j].tag)
+	})
+	// a media type whose most specific range has q=0 is not acceptable,
+	// even to the wildcards
+	acceptable := func(mediaType string) bool {
+		q, matched := 0.0, -1
+		for _, r := range ranges {
+			if s := specificity(r.tag); s > matched && matchesRange(r.tag, mediaType) {
+				q, matched = r.q, s
+			}
+		}
+		return matched < 0 || q > 0
+	}
+	for _, r := range ranges {
+		if r.q <= 0 {
+			continue
+		}
+		if registered, ok := matchEncoder(list, r.tag, acceptable); ok {
+			return registered.mediaType, registered.encoder, true
+		}
+	}
+	return "", nil, false
+}
+
+func matchEncoder(list []registeredEncoder, mediaRange string, acceptable func(mediaType string) bool) (registeredEncoder, bool) {
+	switch {
+	case mediaRange == "*/*" || mediaRange == "*", strings.HasSuffix(mediaRange, "/*"):
+		for _, registered := range list {
+			if matchesRange(mediaRange, registered.mediaType) && acceptable(registered.mediaType) {
+				return registered, true
+			}
+		}
+		return registeredEncoder{}, false
+	}
+	for _, registered := range list {
+		if registered.mediaType == mediaRange {
+			return registered, true
+		}
+	}
+	if i := strings.LastIndex(mediaRange, "+"); i >= 0 {
+		mediaType := "application/" + mediaRange[i+1:]
+		if !acceptable(mediaType) {
+			return registeredEncoder{}, false
+		}
+		return matchEncoder(list, mediaType, acceptable)
+	}
+	return registeredEncoder{}, false
+}
+
+// Whether a media range like */*, text/* or text/xml matches a media type
+func matchesRange(mediaRange, mediaType string) bool {
+	switch {
+	case mediaRange == "*/*" || mediaRange == "*":
+		return true
+	case strings.HasSuffix(mediaRange, "/*"):
+		return strings.HasPrefix(mediaType, strings.TrimSuffix(mediaRange, "*"))
+	}
+	return mediaRange == mediaType
+}
+
+func specificity(mediaRange string) int {
+	switch {
+	case mediaRange == "*/*" || mediaRange == "*":
+		return 0
+	case strings.HasSuffix(mediaRange, "/*"):
+		return 1
+	}
+	return 2
+}
+
+// Get the media type of problem documents encoded as mediaType
+func problemMediaType(mediaType string) string {
+	switch mediaType {
+	case MediaTypeJSON:
+		return ProblemContentType
+	case MediaTypeXML, "text/xml":
+		return ProblemXMLContentType
+	}
+	return mediaType
+}
+
+// JSONEncoder encodes response bodies as JSON
+var JSONEncoder Encoder = EncoderFunc(func(w io.Writer, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+})
+
+// Convert a value to nil, bool, int64, float64, string, []interface{}
+// and map[string]interface{} values through its JSON representation, so
+// json struct tags and json.Marshaler are honoured by every encoder
+func normalize(v interface{}) (interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	var generic interface{}
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, err
+	}
+	return normalizeNumbers(generic), nil
+}
+
+func normalizeNumbers(v interface{}) interface{} {
+	switch value := v.(type) {
+	case json.Number:
+		if i, err := value.Int64(); err == nil {
+			return i
+		}
+		f, _ := value.Float64()
+		return f
+	case []interface{}:
+		for i, item := range value {
+			value[i] = normalizeNumbers(item)
+		}
+	case map[string]interface{}:
+		for k, item := range value {
+			value[k] = normalizeNumbers(item)
+		}
+	}
+	return v
+}
+
+// Get the keys of a map in order
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
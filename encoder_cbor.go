@@ -0,0 +1,116 @@
+package respond
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+)
+
+// CBOR major types
+const (
+	cborUnsigned byte = 0 << 5
+	cborNegative byte = 1 << 5
+	cborText     byte = 3 << 5
+	cborArray    byte = 4 << 5
+	cborMap      byte = 5 << 5
+)
+
+// CBOREncoder encodes response bodies as CBOR (RFC 8949), map keys are
+// written in the deterministic order of the RFC
+var CBOREncoder Encoder = EncoderFunc(func(w io.Writer, v interface{}) error {
+	generic, err := normalize(v)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	if err := encodeCBOR(bw, generic); err != nil {
+		return err
+	}
+	return bw.Flush()
+})
+
+func encodeCBOR(w *bufio.Writer, v interface{}) error {
+	switch value := v.(type) {
+	case nil:
+		return w.WriteByte(0xf6)
+	case bool:
+		if value {
+			return w.WriteByte(0xf5)
+		}
+		return w.WriteByte(0xf4)
+	case int64:
+		if value < 0 {
+			return writeCBORHeader(w, cborNegative, uint64(-(value + 1)))
+		}
+		return writeCBORHeader(w, cborUnsigned, uint64(value))
+	case float64:
+		var b [9]byte
+		b[0] = 0xfb
+		binary.BigEndian.PutUint64(b[1:], math.Float64bits(value))
+		_, err := w.Write(b[:])
+		return err
+	case string:
+		if err := writeCBORHeader(w, cborText, uint64(len(value))); err != nil {
+			return err
+		}
+		_, err := w.WriteString(value)
+		return err
+	case []interface{}:
+		if err := writeCBORHeader(w, cborArray, uint64(len(value))); err != nil {
+			return err
+		}
+		for _, item := range value {
+			if err := encodeCBOR(w, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	case map[string]interface{}:
+		if err := writeCBORHeader(w, cborMap, uint64(len(value))); err != nil {
+			return err
+		}
+		keys := sortedKeys(value)
+		sort.SliceStable(keys, func(i, j int) bool {
+			return len(keys[i]) < len(keys[j])
+		})
+		for _, k := range keys {
+			if err := encodeCBOR(w, k); err != nil {
+				return err
+			}
+			if err := encodeCBOR(w, value[k]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("respond: can not encode %T as cbor", v)
+}
+
+func writeCBORHeader(w *bufio.Writer, major byte, n uint64) error {
+	var b [9]byte
+	switch {
+	case n < 24:
+		return w.WriteByte(major | byte(n))
+	case n <= math.MaxUint8:
+		b[0], b[1] = major|24, byte(n)
+		_, err := w.Write(b[:2])
+		return err
+	case n <= math.MaxUint16:
+		b[0] = major | 25
+		binary.BigEndian.PutUint16(b[1:], uint16(n))
+		_, err := w.Write(b[:3])
+		return err
+	case n <= math.MaxUint32:
+		b[0] = major | 26
+		binary.BigEndian.PutUint32(b[1:], uint32(n))
+		_, err := w.Write(b[:5])
+		return err
+	}
+	b[0] = major | 27
+	binary.BigEndian.PutUint64(b[1:], n)
+	_, err := w.Write(b[:])
+	return err
+}
@@ -0,0 +1,133 @@
+package respond
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+// MessagePackEncoder encodes response bodies as MessagePack, map keys
+// are written in order so equal values are encoded to equal bytes
+var MessagePackEncoder Encoder = EncoderFunc(func(w io.Writer, v interface{}) error {
+	generic, err := normalize(v)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	if err := encodeMessagePack(bw, generic); err != nil {
+		return err
+	}
+	return bw.Flush()
+})
+
+func encodeMessagePack(w *bufio.Writer, v interface{}) error {
+	var b [9]byte
+	switch value := v.(type) {
+	case nil:
+		return w.WriteByte(0xc0)
+	case bool:
+		if value {
+			return w.WriteByte(0xc3)
+		}
+		return w.WriteByte(0xc2)
+	case int64:
+		switch {
+		case value >= 0 && value <= math.MaxInt8:
+			return w.WriteByte(byte(value))
+		case value < 0 && value >= -32:
+			return w.WriteByte(byte(int8(value)))
+		case value >= 0 && value <= math.MaxUint8:
+			b[0], b[1] = 0xcc, byte(value)
+			_, err := w.Write(b[:2])
+			return err
+		case value >= 0 && value <= math.MaxUint16:
+			b[0] = 0xcd
+			binary.BigEndian.PutUint16(b[1:], uint16(value))
+			_, err := w.Write(b[:3])
+			return err
+		case value >= 0 && value <= math.MaxUint32:
+			b[0] = 0xce
+			binary.BigEndian.PutUint32(b[1:], uint32(value))
+			_, err := w.Write(b[:5])
+			return err
+		case value >= math.MinInt8 && value < 0:
+			b[0], b[1] = 0xd0, byte(int8(value))
+			_, err := w.Write(b[:2])
+			return err
+		case value >= math.MinInt16 && value < 0:
+			b[0] = 0xd1
+			binary.BigEndian.PutUint16(b[1:], uint16(int16(value)))
+			_, err := w.Write(b[:3])
+			return err
+		case value >= math.MinInt32 && value < 0:
+			b[0] = 0xd2
+			binary.BigEndian.PutUint32(b[1:], uint32(int32(value)))
+			_, err := w.Write(b[:5])
+			return err
+		}
+		b[0] = 0xd3
+		binary.BigEndian.PutUint64(b[1:], uint64(value))
+		_, err := w.Write(b[:9])
+		return err
+	case float64:
+		b[0] = 0xcb
+		binary.BigEndian.PutUint64(b[1:], math.Float64bits(value))
+		_, err := w.Write(b[:9])
+		return err
+	case string:
+		if err := writeMessagePackHeader(w, len(value), 0xa0, 31, 0xd9, 0xda, 0xdb); err != nil {
+			return err
+		}
+		_, err := w.WriteString(value)
+		return err
+	case []interface{}:
+		if err := writeMessagePackHeader(w, len(value), 0x90, 15, 0, 0xdc, 0xdd); err != nil {
+			return err
+		}
+		for _, item := range value {
+			if err := encodeMessagePack(w, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	case map[string]interface{}:
+		if err := writeMessagePackHeader(w, len(value), 0x80, 15, 0, 0xde, 0xdf); err != nil {
+			return err
+		}
+		for _, k := range sortedKeys(value) {
+			if err := encodeMessagePack(w, k); err != nil {
+				return err
+			}
+			if err := encodeMessagePack(w, value[k]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("respond: can not encode %T as msgpack", v)
+}
+
+// Write the header of a string, array or map of length n, the fixed
+// format is used up to fixMax and the 8 bit format only when it exists
+func writeMessagePackHeader(w *bufio.Writer, n int, fix byte, fixMax int, f8, f16, f32 byte) error {
+	var b [5]byte
+	switch {
+	case n <= fixMax:
+		return w.WriteByte(fix | byte(n))
+	case f8 != 0 && n <= math.MaxUint8:
+		b[0], b[1] = f8, byte(n)
+		_, err := w.Write(b[:2])
+		return err
+	case n <= math.MaxUint16:
+		b[0] = f16
+		binary.BigEndian.PutUint16(b[1:], uint16(n))
+		_, err := w.Write(b[:3])
+		return err
+	}
+	b[0] = f32
+	binary.BigEndian.PutUint32(b[1:], uint32(n))
+	_, err := w.Write(b[:5])
+	return err
+}
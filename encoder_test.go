@@ -0,0 +1,179 @@
+package respond
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiateEncoder(t *testing.T) {
+
+	t.Parallel()
+
+	cases := []struct {
+		accept   string
+		expected string
+	}{
+		{"", MediaTypeJSON},
+		{"*/*", MediaTypeJSON},
+		{"application/xml", MediaTypeXML},
+		{"text/*", "text/xml"},
+		{"application/x-yaml", "application/x-yaml"},
+		{"*/*, application/cbor", MediaTypeCBOR},
+		{"application/json;q=0.5, application/msgpack", MediaTypeMessagePack},
+		{"application/problem+json", MediaTypeJSON},
+		{"text/html, application/yaml;q=0.1", MediaTypeYAML},
+		{"application/json;q=0, */*", MediaTypeXML},
+		{"application/*;q=0, application/cbor;q=0.5, */*", "text/xml"},
+		{"application/*;q=0, */*;q=0.5, application/cbor", MediaTypeCBOR},
+	}
+
+	for _, c := range cases {
+		mediaType, _, ok := NegotiateEncoder(c.accept)
+		assert.True(t, ok, c.accept)
+		assert.Equal(t, c.expected, mediaType, c.accept)
+	}
+
+	for _, accept := range []string{
+		"text/html, image/png",
+		"*/*;q=0",
+		"application/json;q=0, application/problem+json",
+		"application/*;q=0, text/*;q=0, */*",
+	} {
+		_, _, ok := NegotiateEncoder(accept)
+		assert.False(t, ok, accept)
+	}
+}
+
+func TestXMLResponse(t *testing.T) {
+
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("Accept", "application/xml")
+
+	NewWithRequest(recorder, request).NotFound()
+
+	assert.Equal(t, http.StatusNotFound, recorder.Result().StatusCode)
+	assert.Equal(t, MediaTypeXML+"; charset=utf-8", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>`+"\n"+
+		`<response><error>5404</error><message>Oops... The requested page not found!</message><status>failed</status></response>`,
+		recorder.Body.String())
+}
+
+func TestXMLInvalidElementNames(t *testing.T) {
+
+	t.Parallel()
+
+	var b bytes.Buffer
+	assert.NoError(t, XMLEncoder.Encode(&b, map[string]interface{}{
+		"5404": []string{"a", "b"},
+		"ok":   true,
+	}))
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>`+"\n"+
+		`<response><item key="5404"><item>a</item><item>b</item></item><ok>true</ok></response>`,
+		b.String())
+}
+
+func TestProblemXMLResponse(t *testing.T) {
+
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("Accept", "application/xml")
+
+	NewWithRequest(recorder, request).SetMode(ModeProblem).MethodNotAllowed()
+
+	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Result().StatusCode)
+	assert.Equal(t, "application/problem+xml; charset=utf-8", recorder.Header().Get("Content-Type"))
+	assert.Contains(t, recorder.Body.String(), `<problem xmlns="urn:ietf:rfc:7807">`)
+	assert.Contains(t, recorder.Body.String(), `<status>405</status>`)
+	assert.True(t, strings.HasSuffix(recorder.Body.String(), "</problem>"))
+}
+
+func TestYAMLResponse(t *testing.T) {
+
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("Accept", "application/yaml")
+
+	NewWithRequest(recorder, request).Succeed(map[string]interface{}{
+		"data": "Test",
+	})
+
+	assert.Equal(t, MediaTypeYAML+"; charset=utf-8", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, "result:\n  data: Test\nstatus: success\n", recorder.Body.String())
+}
+
+func TestMessagePackResponse(t *testing.T) {
+
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("Accept", "application/msgpack")
+
+	NewWithRequest(recorder, request).Succeed(1)
+
+	assert.Equal(t, MediaTypeMessagePack, recorder.Header().Get("Content-Type"))
+	expected := []byte{0x82, 0xa6}
+	expected = append(expected, "result"...)
+	expected = append(expected, 0x01, 0xa6)
+	expected = append(expected, "status"...)
+	expected = append(expected, 0xa7)
+	expected = append(expected, "success"...)
+	assert.Equal(t, expected, recorder.Body.Bytes())
+}
+
+func TestCBORResponse(t *testing.T) {
+
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("Accept", "application/cbor")
+
+	NewWithRequest(recorder, request).Succeed([]interface{}{-1, nil, 500})
+
+	assert.Equal(t, MediaTypeCBOR, recorder.Header().Get("Content-Type"))
+
+	expected := []byte{0xa2, 0x66}
+	expected = append(expected, "result"...)
+	expected = append(expected, 0x83, 0x20, 0xf6, 0x19, 0x01, 0xf4, 0x66)
+	expected = append(expected, "status"...)
+	expected = append(expected, 0x67)
+	expected = append(expected, "success"...)
+	assert.Equal(t, expected, recorder.Body.Bytes())
+}
+
+func TestNotAcceptable(t *testing.T) {
+
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("Accept", "text/html")
+
+	err := NewWithRequest(recorder, request).Succeed("Test")
+	assert.ErrorIs(t, err, ErrNotAcceptable)
+
+	assert.Equal(t, http.StatusNotAcceptable, recorder.Result().StatusCode)
+	assert.Equal(t, MediaTypeJSON+"; charset=utf-8", recorder.Header().Get("Content-Type"))
+
+	expected, err := getExpectedMap(recorder.Body)
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"status":  "failed",
+		"error":   float64(5406),
+		"message": "Oops... The parameters you entered are wrong!",
+	}, expected)
+}
@@ -0,0 +1,118 @@
+package respond
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+	"unicode"
+)
+
+// XMLRootElement is the name of the element which wraps XML responses
+var XMLRootElement = "response"
+
+// XMLEncoder encodes response bodies as XML
+//
+// Maps become elements named after their keys, keys which are not valid
+// element names like error codes become <item key="5404"> elements and
+// array values are written as repeated <item> elements
+//
+//      <response>
+//        <result><item>a</item><item>b</item></result>
+//        <status>success</status>
+//      </response>
+var XMLEncoder Encoder = EncoderFunc(func(w io.Writer, v interface{}) error {
+	return encodeXMLDocument(w, xml.StartElement{Name: xml.Name{Local: XMLRootElement}}, v)
+})
+
+// ProblemXMLEncoder encodes the problem details of application/problem+xml
+// responses, their root is the problem element of RFC 7807 Appendix A
+//
+//      <problem xmlns="urn:ietf:rfc:7807">
+//        <status>404</status>
+//        <title>Not Found</title>
+//      </problem>
+var ProblemXMLEncoder Encoder = EncoderFunc(func(w io.Writer, v interface{}) error {
+	return encodeXMLDocument(w, xml.StartElement{
+		Name: xml.Name{Local: "problem"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "xmlns"}, Value: "urn:ietf:rfc:7807"}},
+	}, v)
+})
+
+func encodeXMLDocument(w io.Writer, root xml.StartElement, v interface{}) error {
+	generic, err := normalize(v)
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	e := xml.NewEncoder(w)
+	if err := encodeXML(e, root, generic); err != nil {
+		return err
+	}
+	return e.Flush()
+}
+
+func encodeXML(e *xml.Encoder, start xml.StartElement, v interface{}) error {
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	switch value := v.(type) {
+	case nil:
+	case map[string]interface{}:
+		for _, k := range sortedKeys(value) {
+			if err := encodeXML(e, xmlElement(k), value[k]); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, item := range value {
+			if err := encodeXML(e, xml.StartElement{Name: xml.Name{Local: "item"}}, item); err != nil {
+				return err
+			}
+		}
+	case string:
+		if err := e.EncodeToken(xml.CharData(value)); err != nil {
+			return err
+		}
+	case bool:
+		if err := e.EncodeToken(xml.CharData(strconv.FormatBool(value))); err != nil {
+			return err
+		}
+	case int64:
+		if err := e.EncodeToken(xml.CharData(strconv.FormatInt(value, 10))); err != nil {
+			return err
+		}
+	case float64:
+		if err := e.EncodeToken(xml.CharData(strconv.FormatFloat(value, 'g', -1, 64))); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+func xmlElement(key string) xml.StartElement {
+	if isXMLName(key) {
+		return xml.StartElement{Name: xml.Name{Local: key}}
+	}
+	return xml.StartElement{
+		Name: xml.Name{Local: "item"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "key"}, Value: key}},
+	}
+}
+
+func isXMLName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, c := range name {
+		switch {
+		case c == '_' || unicode.IsLetter(c):
+		case i > 0 && (c == '-' || c == '.' || unicode.IsDigit(c)):
+		default:
+			return false
+		}
+	}
+	return len(name) < 3 || !(name[0] == 'x' || name[0] == 'X') ||
+		!(name[1] == 'm' || name[1] == 'M') || !(name[2] == 'l' || name[2] == 'L')
+}
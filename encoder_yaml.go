@@ -0,0 +1,21 @@
+package respond
+
+import (
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// YAMLEncoder encodes response bodies as YAML
+var YAMLEncoder Encoder = EncoderFunc(func(w io.Writer, v interface{}) error {
+	generic, err := normalize(v)
+	if err != nil {
+		return err
+	}
+	e := yaml.NewEncoder(w)
+	e.SetIndent(2)
+	if err := e.Encode(generic); err != nil {
+		return err
+	}
+	return e.Close()
+})
@@ -0,0 +1,170 @@
+package respond
+
+// EnvelopeData is what the envelope of a response body wraps
+type EnvelopeData struct {
+	// Success is false for responses with an error status
+	Success bool
+
+	// Status is the localised status text, like success or failed
+	Status string
+
+	// Result of the response, HasResult reports whether it has one
+	Result    interface{}
+	HasResult bool
+
+	// Message of the response, HasMessage reports whether it has one
+	Message    interface{}
+	HasMessage bool
+
+	// Code is the catalogued error code, 0 when there is none
+	Code int
+
+	// Details of an error, like the validation errors
+	Details interface{}
+
+	// Meta holds extra information of the result, like the pagination
+	Meta map[string]interface{}
+
+	// Extra members which are added to the envelope as they are, like
+	// the panic of a recovered request in debug mode
+	Extra map[string]interface{}
+}
+
+// Envelope describes the members of response bodies. Empty keys use the
+// default names and a key of "-" leaves the member out
+//
+//      respond.Envelope{
+//        StatusKey:  "success",
+//        BoolStatus: true,
+//        ResultKey:  "data",
+//        NestError:  true,
+//      }
+//      // {"success":false,"error":{"code":5404,"message":"..."}}
+type Envelope struct {
+	// Key of the status, which is the localised status text or true and
+	// false when BoolStatus is set
+	StatusKey  string
+	BoolStatus bool
+
+	// Keys of the result, the message and the meta of responses
+	ResultKey  string
+	MessageKey string
+	MetaKey    string
+
+	// Key of the error code, or of the error object when NestError is set
+	ErrorKey string
+
+	// NestError moves the code, the message and the details of errors
+	// into an object under ErrorKey, with CodeKey, MessageKey and
+	// DetailsKey. Without it the details of errors are the result
+	NestError  bool
+	CodeKey    string
+	DetailsKey string
+
+	// OmitNilResult leaves the result out when it is nil
+	OmitNilResult bool
+
+	// Wrap builds the whole body of responses, the other fields are not
+	// used when it is set
+	Wrap func(data EnvelopeData) interface{}
+}
+
+// DefaultEnvelope is the envelope of new Respond instances
+var DefaultEnvelope = Envelope{
+	StatusKey:  "status",
+	ResultKey:  "result",
+	MessageKey: "message",
+	MetaKey:    "meta",
+	ErrorKey:   "error",
+	CodeKey:    "code",
+	DetailsKey: "details",
+}
+
+// Set the envelope of the response bodies
+//
+// @param envelope Envelope
+// @return *Respond
+func (r *Respond) SetEnvelope(envelope Envelope) *Respond {
+	r.envelope = envelope
+	return r
+}
+
+// Wrap the data of a response in the envelope
+//
+// @param data EnvelopeData
+// @return interface{}
+func (e Envelope) Build(data EnvelopeData) interface{} {
+	if e.Wrap != nil {
+		return e.Wrap(data)
+	}
+	body := map[string]interface{}{}
+	set := func(m map[string]interface{}, key, fallback string, value interface{}) {
+		if key == "" {
+			key = fallback
+		}
+		if key != "-" {
+			m[key] = value
+		}
+	}
+
+	if e.BoolStatus {
+		set(body, e.StatusKey, "status", data.Success)
+	} else {
+		set(body, e.StatusKey, "status", data.Status)
+	}
+	if data.HasResult && !(e.OmitNilResult && data.Result == nil) {
+		set(body, e.ResultKey, "result", data.Result)
+	}
+	if len(data.Meta) > 0 {
+		set(body, e.MetaKey, "meta", data.Meta)
+	}
+
+	if !e.NestError {
+		if data.Details != nil {
+			set(body, e.ResultKey, "result", data.Details)
+		}
+		if data.HasMessage {
+			set(body, e.MessageKey, "message", data.Message)
+			if data.Code != 0 {
+				set(body, e.ErrorKey, "error", data.Code)
+			}
+		}
+	} else if data.Success {
+		if data.HasMessage {
+			set(body, e.MessageKey, "message", data.Message)
+		}
+	} else {
+		object := map[string]interface{}{}
+		if data.Code != 0 {
+			set(object, e.CodeKey, "code", data.Code)
+		}
+		if data.HasMessage {
+			set(object, e.MessageKey, "message", data.Message)
+		}
+		if data.Details != nil {
+			set(object, e.DetailsKey, "details", data.Details)
+		}
+		set(body, e.ErrorKey, "error", object)
+	}
+
+	for k, v := range data.Extra {
+		body[k] = v
+	}
+	return body
+}
+
+// Get the member of the error record of streams, which is the error
+// object of nested errors and the whole envelope otherwise
+func (e Envelope) errorRecord(data EnvelopeData) (string, interface{}) {
+	key := e.ErrorKey
+	if key == "" || key == "-" {
+		key = "error"
+	}
+	record := e.Build(data)
+	if body, ok := record.(map[string]interface{}); ok && e.NestError && e.Wrap == nil {
+		if object, ok := body[key]; ok {
+			return key, object
+		}
+	}
+	return key, record
+}
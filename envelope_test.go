@@ -0,0 +1,132 @@
+package respond
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// The envelope of the API guideline
+var guidelineEnvelope = Envelope{
+	StatusKey:     "success",
+	BoolStatus:    true,
+	ResultKey:     "data",
+	MetaKey:       "meta",
+	NestError:     true,
+	OmitNilResult: true,
+}
+
+func TestDefaultEnvelope(t *testing.T) {
+
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+	assert.NoError(t, NewWithWriter(recorder).Succeed(map[string]int{"id": 1}))
+	assert.JSONEq(t, `{"status":"success","result":{"id":1}}`, recorder.Body.String())
+
+	recorder = httptest.NewRecorder()
+	assert.NoError(t, NewWithWriter(recorder).NotFound())
+	assert.JSONEq(t, `{"status":"failed","error":5404,"message":"Oops... The requested page not found!"}`, recorder.Body.String())
+}
+
+func TestGuidelineEnvelope(t *testing.T) {
+
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+	assert.NoError(t, NewWithWriter(recorder).SetEnvelope(guidelineEnvelope).Succeed([]int{1, 2}))
+	assert.JSONEq(t, `{"success":true,"data":[1,2]}`, recorder.Body.String())
+
+	recorder = httptest.NewRecorder()
+	assert.NoError(t, NewWithWriter(recorder).SetEnvelope(guidelineEnvelope).Succeed(nil))
+	assert.JSONEq(t, `{"success":true}`, recorder.Body.String())
+
+	recorder = httptest.NewRecorder()
+	assert.NoError(t, NewWithWriter(recorder).SetEnvelope(guidelineEnvelope).NotFound())
+	assert.JSONEq(t, `{"success":false,"error":{"code":5404,"message":"Oops... The requested page not found!"}}`, recorder.Body.String())
+
+	recorder = httptest.NewRecorder()
+	assert.NoError(t, NewWithWriter(recorder).SetEnvelope(guidelineEnvelope).ValidationErrors(map[string]string{"email": "required"}))
+	assert.Contains(t, recorder.Body.String(), `"details":{"email":"required"}`)
+	assert.Contains(t, recorder.Body.String(), `"success":false`)
+}
+
+func TestEnvelopeOmitsMembers(t *testing.T) {
+
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+	assert.NoError(t, NewWithWriter(recorder).SetEnvelope(Envelope{StatusKey: "-", ErrorKey: "code"}).NotFound())
+	assert.JSONEq(t, `{"code":5404,"message":"Oops... The requested page not found!"}`, recorder.Body.String())
+}
+
+func TestEnvelopeWrap(t *testing.T) {
+
+	t.Parallel()
+
+	envelope := Envelope{Wrap: func(data EnvelopeData) interface{} {
+		if !data.Success {
+			return map[string]interface{}{"ok": false, "reason": data.Code}
+		}
+		return data.Result
+	}}
+
+	recorder := httptest.NewRecorder()
+	assert.NoError(t, NewWithWriter(recorder).SetEnvelope(envelope).Succeed([]int{1, 2}))
+	assert.JSONEq(t, `[1,2]`, recorder.Body.String())
+
+	recorder = httptest.NewRecorder()
+	assert.NoError(t, NewWithWriter(recorder).SetEnvelope(envelope).NotFound())
+	assert.JSONEq(t, `{"ok":false,"reason":5404}`, recorder.Body.String())
+
+	// a bare array is streamed when the envelope is the result
+	recorder = httptest.NewRecorder()
+	assert.NoError(t, NewWithWriter(recorder).SetEnvelope(envelope).Stream(context.Background(), Iterator(counter(2))))
+	assert.Equal(t, `[1,2]`, recorder.Body.String())
+}
+
+func TestEnvelopeMiddleware(t *testing.T) {
+
+	t.Parallel()
+
+	handler := Middleware(Options{Envelope: &guidelineEnvelope})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		From(r).Succeed("ok")
+	}))
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.JSONEq(t, `{"success":true,"data":"ok"}`, recorder.Body.String())
+}
+
+func TestEnvelopeStream(t *testing.T) {
+
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+	assert.NoError(t, NewWithWriter(recorder).SetEnvelope(guidelineEnvelope).Stream(context.Background(), Iterator(counter(2))))
+	assert.Equal(t, `{"success":true,"data":[1,2]}`, recorder.Body.String())
+
+	recorder = httptest.NewRecorder()
+	failed := errors.New("failed")
+	i := 0
+	next := func() (interface{}, bool, error) {
+		if i++; i > 1 {
+			return nil, false, failed
+		}
+		return i, true, nil
+	}
+	assert.ErrorIs(t, NewWithWriter(recorder).SetEnvelope(guidelineEnvelope).Stream(context.Background(), Iterator(next)), failed)
+	assert.Equal(t, `{"success":true,"data":[1],"error":{"code":5500,"message":"Oops... Something went wrong on our side!"}}`, recorder.Body.String())
+}
+
+// Iterator of the numbers from 1 to n
+func counter(n int) Iterator {
+	i := 0
+	return func() (interface{}, bool, error) {
+		i++
+		return i, i <= n, nil
+	}
+}
@@ -0,0 +1,112 @@
+package respond
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+var (
+	// ErrAlreadyWritten is returned when a response is written twice
+	ErrAlreadyWritten = errors.New("respond: response is already written")
+
+	// ErrEncode is matched by the errors of failed encodings
+	ErrEncode = errors.New("respond: can not encode response")
+
+	// ErrNotAcceptable is returned when the request accepts none of the
+	// media types, the response is written as 406 Not Acceptable instead
+	ErrNotAcceptable = errors.New("respond: no acceptable media type")
+)
+
+// EncodeError is returned when the encoder of the response fails,
+// errors.Is(err, ErrEncode) reports true for it
+type EncodeError struct {
+	MediaType string
+	Err       error
+}
+
+func (e *EncodeError) Error() string {
+	return fmt.Sprintf("respond: can not encode response as %s: %v", e.MediaType, e.Err)
+}
+
+func (e *EncodeError) Unwrap() error {
+	return e.Err
+}
+
+func (e *EncodeError) Is(target error) bool {
+	return target == ErrEncode
+}
+
+// Error is a catalogued error which services can return from deep in the
+// stack, wrap with fmt.Errorf("%w") and pass to Respond.Err
+type Error struct {
+	// HTTP status of the response
+	Status int
+
+	// Code of the error in the catalog, like 3010
+	Code int
+
+	// The cat and short fields of the catalog entry
+	Cat   string
+	Short string
+
+	// Key of the message in the catalog, like errors.3010.message
+	Key string
+}
+
+func (e *Error) Error() string {
+	if e.Short == "" {
+		return fmt.Sprintf("respond: error %d", e.Code)
+	}
+	return fmt.Sprintf("respond: error %d %s", e.Code, e.Short)
+}
+
+// Is reports whether target is an *Error with the same code
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+var errorRegistry = struct {
+	codes map[int]*Error
+	sync.RWMutex
+}{codes: map[int]*Error{}}
+
+// Register a catalogued error, the message key of the error is
+// errors.<code>.message. A registered error with the same code is
+// replaced
+//
+//	var ErrQuotaExceeded = respond.RegisterError(429, 6001, "billing", "quota-exceeded")
+//
+// @param status int, code int, cat string, short string
+// @return *Error
+func RegisterError(status, code int, cat, short string) *Error {
+	e := &Error{
+		Status: status,
+		Code:   code,
+		Cat:    cat,
+		Short:  short,
+		Key:    "errors." + strconv.Itoa(code) + ".message",
+	}
+	errorRegistry.Lock()
+	errorRegistry.codes[code] = e
+	errorRegistry.Unlock()
+	return e
+}
+
+// Get the registered error of a code
+//
+// @param code int
+// @return (*Error, bool)
+func LookupError(code int) (*Error, bool) {
+	errorRegistry.RLock()
+	defer errorRegistry.RUnlock()
+	e, ok := errorRegistry.codes[code]
+	return e, ok
+}
+
+// The codes, errors and helpers of the built-in catalog are generated
+// from the catalog to codes_gen.go
+//
+//go:generate go run ./cmd/respond-gen -o codes_gen.go
@@ -0,0 +1,98 @@
+package respond
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorRegistry(t *testing.T) {
+
+	t.Parallel()
+
+	e, ok := LookupError(3010)
+	assert.True(t, ok)
+	assert.Equal(t, ErrTokenExpired, e)
+	assert.Equal(t, &Error{
+		Status: http.StatusUnauthorized,
+		Code:   3010,
+		Cat:    "auth",
+		Short:  "token-expired",
+		Key:    "errors.3010.message",
+	}, e)
+	assert.Equal(t, "respond: error 3010 token-expired", e.Error())
+
+	_, ok = LookupError(1)
+	assert.False(t, ok)
+}
+
+func TestErrorIsAs(t *testing.T) {
+
+	t.Parallel()
+
+	err := fmt.Errorf("users: find 12: %w", ErrDatabaseConnectionRefused)
+
+	assert.True(t, errors.Is(err, ErrDatabaseConnectionRefused))
+	assert.True(t, errors.Is(err, &Error{Code: 5445}))
+	assert.False(t, errors.Is(err, ErrNotFound))
+
+	var e *Error
+	assert.True(t, errors.As(err, &e))
+	assert.Equal(t, 5445, e.Code)
+}
+
+func TestErr(t *testing.T) {
+
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+	err := NewWithWriter(recorder).Language("fa").Err(fmt.Errorf("auth: %w", ErrTokenExpired))
+	assert.NoError(t, err)
+
+	assert.Equal(t, http.StatusUnauthorized, recorder.Result().StatusCode)
+
+	expected, err := getExpectedMap(recorder.Body)
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"status":  "نا موفق",
+		"error":   float64(3010),
+		"message": ".زمان استفاده از نشان شناسایی شما گذشته است",
+	}, expected)
+}
+
+func TestErrUnknown(t *testing.T) {
+
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+	assert.NoError(t, NewWithWriter(recorder).Err(errors.New("boom")))
+
+	assert.Equal(t, http.StatusInternalServerError, recorder.Result().StatusCode)
+
+	expected, err := getExpectedMap(recorder.Body)
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"status":  "failed",
+		"error":   float64(5500),
+		"message": "Oops... Something went wrong on our side!",
+	}, expected)
+}
+
+func TestErrNil(t *testing.T) {
+
+	t.Parallel()
+
+	var (
+		recorder = httptest.NewRecorder()
+		respond  = NewWithWriter(recorder)
+	)
+
+	assert.NoError(t, respond.Err(nil))
+	assert.False(t, respond.Written())
+}
@@ -0,0 +1,238 @@
+// Package gettext exports the message catalogs of respond to gettext
+// .pot and .po files and imports translated .po and .mo files back into
+// catalogs which Messages.AddLanguageTranslation accepts.
+//
+// Every string of a catalog is an entry whose msgctxt is the dotted key
+// of the string, like errors.5404.message, and whose msgid is the string
+// of the source language. The type, cat and short fields of errors are
+// exported as entries too, so a round trip through .po or .mo files
+// keeps them.
+//
+// Plural messages are msgid_plural entries whose msgstr[n] forms are
+// mapped to the CLDR plural forms of the message by the Plural-Forms
+// header of the file, or in the order respond.PluralCategories gives for
+// the language of a file without one.
+package gettext
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	respond "github.com/mrjosh/respond.go"
+)
+
+// Entry is a message of a catalog
+type Entry struct {
+	// Context is the dotted key of the message, like errors.5404.message
+	Context string
+
+	// ID is the message in the source language
+	ID string
+
+	// IDPlural is the plural message in the source language, it is only
+	// set for plural messages
+	IDPlural string
+
+	// Str is the translated message, the first form of a plural message
+	Str string
+
+	// Plurals are the translated forms of a plural message by their
+	// CLDR plural category
+	Plurals map[string]string
+
+	// Comments are the extracted comments of the entry
+	Comments []string
+
+	// Fuzzy entries are not imported
+	Fuzzy bool
+}
+
+// Fields of catalog errors which are metadata and not messages
+var metadataFields = map[string]bool{
+	"type":  true,
+	"cat":   true,
+	"short": true,
+}
+
+// Get the entries of a catalog ordered by their keys, the msgid of an
+// entry is the string of the same key in the source catalog, or the key
+// itself when the source catalog does not have it
+//
+// @param catalog map[string]interface{}, source map[string]interface{}
+// @return ([]*Entry, error)
+func Entries(catalog, source map[string]interface{}) ([]*Entry, error) {
+	translations, plurals := map[string]string{}, map[string]map[string]string{}
+	if err := flatten("", catalog, translations, plurals); err != nil {
+		return nil, err
+	}
+	sources, sourcePlurals := map[string]string{}, map[string]map[string]string{}
+	if err := flatten("", source, sources, sourcePlurals); err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(translations)+len(plurals))
+	for key := range translations {
+		keys = append(keys, key)
+	}
+	for key := range plurals {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	entries := make([]*Entry, 0, len(keys))
+	for _, key := range keys {
+		forms, plural := plurals[key]
+		if !plural {
+			id, ok := sources[key]
+			if !ok || id == "" {
+				id = key
+			}
+			entry := &Entry{Context: key, ID: id, Str: translations[key]}
+			if isMetadata(key) {
+				entry.Comments = []string{"metadata of the error, do not translate"}
+			}
+			entries = append(entries, entry)
+			continue
+		}
+		id, idPlural := key, key
+		if source, ok := sourcePlurals[key]; ok {
+			id, idPlural = source[respond.PluralOne], source[respond.PluralOther]
+			if id == "" {
+				id = idPlural
+			}
+		} else if source := sources[key]; source != "" {
+			id, idPlural = source, source
+		}
+		entries = append(entries, &Entry{
+			Context:  key,
+			ID:       id,
+			IDPlural: idPlural,
+			Str:      forms[respond.PluralOther],
+			Plurals:  forms,
+		})
+	}
+	return entries, nil
+}
+
+// Build a catalog from entries, untranslated and fuzzy entries are left
+// out so the fallback chain of the language is used for them
+//
+// @param entries []*Entry
+// @return (map[string]interface{}, error)
+func Catalog(entries []*Entry) (map[string]interface{}, error) {
+	catalog := map[string]interface{}{}
+	for _, entry := range entries {
+		if entry.Context == "" || entry.Str == "" || entry.Fuzzy {
+			continue
+		}
+		node := catalog
+		path := strings.Split(entry.Context, ".")
+		for _, key := range path[:len(path)-1] {
+			child, ok := node[key]
+			if !ok {
+				child = map[string]interface{}{}
+				node[key] = child
+			}
+			next, ok := child.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("gettext: %s conflicts with the message of %s", entry.Context, key)
+			}
+			node = next
+		}
+		last := path[len(path)-1]
+		if _, ok := node[last].(map[string]interface{}); ok {
+			return nil, fmt.Errorf("gettext: %s conflicts with the messages under it", entry.Context)
+		}
+		if len(entry.Plurals) == 0 {
+			node[last] = entry.Str
+			continue
+		}
+		forms := make(map[string]interface{}, len(entry.Plurals))
+		for category, form := range entry.Plurals {
+			forms[category] = form
+		}
+		node[last] = forms
+	}
+	return catalog, nil
+}
+
+// Get a field of the header of a .po or .mo file, like Language
+func headerField(header, name string) string {
+	for _, line := range strings.Split(header, "\n") {
+		if i := strings.IndexByte(line, ':'); i >= 0 && strings.EqualFold(strings.TrimSpace(line[:i]), name) {
+			return strings.TrimSpace(line[i+1:])
+		}
+	}
+	return ""
+}
+
+// Collect the strings of nested catalog maps by their dotted keys, the
+// forms of plural messages are collected by the keys of the messages
+func flatten(prefix string, node interface{}, out map[string]string, plurals map[string]map[string]string) error {
+	switch value := node.(type) {
+	case nil:
+		return nil
+	case string:
+		out[prefix] = value
+		return nil
+	case map[string]interface{}:
+		if forms, ok := pluralMessage(value); ok && prefix != "" {
+			plurals[prefix] = forms
+			return nil
+		}
+		for k, v := range value {
+			if err := flatten(join(prefix, k), v, out, plurals); err != nil {
+				return err
+			}
+		}
+		return nil
+	case map[string]map[string]interface{}:
+		for k, v := range value {
+			if err := flatten(join(prefix, k), v, out, plurals); err != nil {
+				return err
+			}
+		}
+		return nil
+	case map[string]string:
+		forms := make(map[string]interface{}, len(value))
+		for k, v := range value {
+			forms[k] = v
+		}
+		return flatten(prefix, forms, out, plurals)
+	}
+	return fmt.Errorf("gettext: %s is a %T, only strings and maps can be exported", prefix, node)
+}
+
+// Get the forms of a plural message, which has an other form and only
+// CLDR plural categories with strings
+func pluralMessage(node map[string]interface{}) (map[string]string, bool) {
+	if _, ok := node[respond.PluralOther]; !ok {
+		return nil, false
+	}
+	forms := make(map[string]string, len(node))
+	for category, form := range node {
+		s, ok := form.(string)
+		if !ok {
+			return nil, false
+		}
+		switch category {
+		case respond.PluralZero, respond.PluralOne, respond.PluralTwo, respond.PluralFew, respond.PluralMany, respond.PluralOther:
+			forms[category] = s
+		default:
+			return nil, false
+		}
+	}
+	return forms, true
+}
+
+func join(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// Whether a key is a metadata field of an error, like errors.3001.cat
+func isMetadata(key string) bool {
+	path := strings.Split(key, ".")
+	return len(path) == 3 && path[0] == "errors" && metadataFields[path[2]]
+}
@@ -0,0 +1,336 @@
+package gettext
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	respond "github.com/mrjosh/respond.go"
+	"github.com/mrjosh/respond.go/translations/en"
+	"github.com/mrjosh/respond.go/translations/fa"
+	"github.com/stretchr/testify/assert"
+)
+
+func flat(t *testing.T, catalog map[string]interface{}) map[string]string {
+	out, plurals := map[string]string{}, map[string]map[string]string{}
+	assert.NoError(t, flatten("", catalog, out, plurals))
+	for key, forms := range plurals {
+		for category, form := range forms {
+			out[key+"."+category] = form
+		}
+	}
+	return out
+}
+
+// A catalog with the plural message errors.items
+func withItems(catalog map[string]interface{}, items map[string]interface{}) map[string]interface{} {
+	c := make(map[string]interface{}, len(catalog))
+	for k, v := range catalog {
+		c[k] = v
+	}
+	errors := map[string]interface{}{"items": items}
+	for code, entry := range catalog["errors"].(map[string]map[string]interface{}) {
+		errors[code] = entry
+	}
+	c["errors"] = errors
+	return c
+}
+
+func TestPORoundTrip(t *testing.T) {
+
+	t.Parallel()
+
+	for lang, catalog := range map[string]map[string]interface{}{
+		"en": en.Messages,
+		"fa": fa.Messages,
+	} {
+		var b bytes.Buffer
+		assert.NoError(t, Export(&b, lang, catalog, en.Messages))
+
+		imported, err := Import(&b)
+		assert.NoError(t, err)
+		assert.Equal(t, flat(t, catalog), flat(t, imported), lang)
+	}
+}
+
+func TestMORoundTrip(t *testing.T) {
+
+	t.Parallel()
+
+	for lang, catalog := range map[string]map[string]interface{}{
+		"en": en.Messages,
+		"fa": fa.Messages,
+	} {
+		var b bytes.Buffer
+		assert.NoError(t, Compile(&b, lang, catalog, en.Messages))
+
+		imported, err := ImportMO(&b)
+		assert.NoError(t, err)
+		assert.Equal(t, flat(t, catalog), flat(t, imported), lang)
+	}
+}
+
+func TestImportedCatalogResponds(t *testing.T) {
+
+	t.Parallel()
+
+	var b bytes.Buffer
+	assert.NoError(t, Export(&b, "fa", fa.Messages, en.Messages))
+	catalog, err := Import(&b)
+	assert.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	r := respond.NewWithWriter(recorder)
+	r.Messages().AddLanguageTranslation("fa-PO", catalog)
+	r.Messages().SetFallback("fa-PO")
+	assert.NoError(t, r.Language("fa-PO").NotFound())
+
+	assert.JSONEq(t, `{"status":"نا موفق","error":5404,"message":".صفحه درخواست شده پیدا نمیشود"}`, recorder.Body.String())
+}
+
+func TestWriteTemplate(t *testing.T) {
+
+	t.Parallel()
+
+	var b bytes.Buffer
+	assert.NoError(t, WriteTemplate(&b, en.Messages))
+
+	assert.True(t, strings.HasPrefix(b.String(), "msgid \"\"\nmsgstr \"\"\n\"MIME-Version: 1.0\\n\"\n"))
+	assert.Contains(t, b.String(), "\n#. metadata of the error, do not translate\n"+
+		"msgctxt \"errors.3001.cat\"\nmsgid \"auth\"\nmsgstr \"\"\n")
+	assert.Contains(t, b.String(), "\nmsgctxt \"errors.5404.message\"\n"+
+		"msgid \"Oops... The requested page not found!\"\nmsgstr \"\"\n")
+
+	entries, err := ReadPO(&b)
+	assert.NoError(t, err)
+	catalog, err := Catalog(entries)
+	assert.NoError(t, err)
+	assert.Empty(t, catalog)
+}
+
+func TestReadPO(t *testing.T) {
+
+	t.Parallel()
+
+	entries, err := ReadPO(strings.NewReader(`# translator comment
+msgid ""
+msgstr ""
+"Language: fa\n"
+
+#. extracted
+msgctxt "errors.1001.message"
+msgid "Oops... Requested field {field} is not found!"
+msgstr ""
+"multi "
+"line \"quoted\""
+
+#, fuzzy
+msgctxt "errors.1002.message"
+msgid "Oops... Requested User does not exists!"
+msgstr "fuzzy"
+msgctxt "success"
+msgid "success"
+msgstr "موفق"
+
+#~ msgctxt "failed"
+#~ msgid "failed"
+#~ msgstr "obsolete"
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, []*Entry{
+		{Context: "errors.1001.message", ID: "Oops... Requested field {field} is not found!", Str: "multi line \"quoted\"", Comments: []string{"extracted"}},
+		{Context: "errors.1002.message", ID: "Oops... Requested User does not exists!", Str: "fuzzy", Fuzzy: true},
+		{Context: "success", ID: "success", Str: "موفق"},
+	}, entries)
+
+	catalog, err := Catalog(entries)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"success": "موفق",
+		"errors": map[string]interface{}{
+			"1001": map[string]interface{}{"message": "multi line \"quoted\""},
+		},
+	}, catalog)
+}
+
+func TestReadPOErrors(t *testing.T) {
+
+	t.Parallel()
+
+	_, err := ReadPO(strings.NewReader("msgid \"a\"\nmsgval \"b\"\n"))
+	assert.EqualError(t, err, `gettext: line 2: unknown keyword "msgval"`)
+
+	_, err = ReadPO(strings.NewReader("\"orphan\"\n"))
+	assert.EqualError(t, err, "gettext: line 1: string without a keyword")
+}
+
+func TestReadMOErrors(t *testing.T) {
+
+	t.Parallel()
+
+	_, err := ReadMO(bytes.NewReader([]byte("short")))
+	assert.EqualError(t, err, "gettext: .mo file is too short")
+
+	_, err = ReadMO(bytes.NewReader(make([]byte, 28)))
+	assert.EqualError(t, err, "gettext: invalid .mo magic number")
+}
+
+func TestReadPOPlurals(t *testing.T) {
+
+	t.Parallel()
+
+	entries, err := ReadPO(strings.NewReader(`msgid ""
+msgstr ""
+"Language: fa\n"
+"Plural-Forms: nplurals=2; plural=(n==0 || n==1 ? 0 : 1);\n"
+
+msgctxt "errors.items"
+msgid "One item"
+msgid_plural "{count} items"
+msgstr[0] "یک مورد"
+msgstr[1] "{count} مورد"
+`))
+	assert.NoError(t, err)
+	catalog, err := Catalog(entries)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"errors": map[string]interface{}{
+			"items": map[string]interface{}{"one": "یک مورد", "other": "{count} مورد"},
+		},
+	}, catalog)
+
+	_, err = ReadPO(strings.NewReader(`msgid ""
+msgstr ""
+"Language: en\n"
+
+msgctxt "errors.items"
+msgid "One item"
+msgid_plural "{count} items"
+msgstr[0] "a"
+msgstr[1] "b"
+msgstr[2] "c"
+`))
+	assert.EqualError(t, err, "gettext: line 5: 3 plural forms do not match the plural categories one, other of en")
+
+	_, err = ReadPO(strings.NewReader("msgid \"item\"\nmsgid_plural \"items\"\nmsgstr[0] \"a\"\nmsgstr[1] \"b\"\n"))
+	assert.EqualError(t, err, "gettext: line 1: 2 plural forms without a Plural-Forms or Language header")
+
+	_, err = ReadPO(strings.NewReader(`msgid ""
+msgstr ""
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+msgid "item"
+msgid_plural "items"
+msgstr[0] "a"
+msgstr[1] "b"
+msgstr[2] "c"
+`))
+	assert.EqualError(t, err, `gettext: line 5: 3 plural forms do not match the Plural-Forms header "nplurals=2; plural=(n != 1);"`)
+}
+
+func TestReadPOPluralForms(t *testing.T) {
+
+	t.Parallel()
+
+	// three forms by the Plural-Forms header, though there is no plural
+	// rule for the languages
+	for _, c := range []struct {
+		lang, plural string
+	}{
+		{"ru", "nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);"},
+		{"pl", "nplurals=3; plural=(n==1 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);"},
+	} {
+		catalog, err := Import(strings.NewReader(`msgid ""
+msgstr ""
+"Language: ` + c.lang + `\n"
+"Plural-Forms: ` + c.plural + `\n"
+
+msgctxt "errors.items"
+msgid "One item"
+msgid_plural "{count} items"
+msgstr[0] "a"
+msgstr[1] "b"
+msgstr[2] "c"
+`))
+		assert.NoError(t, err, c.lang)
+		assert.Equal(t, map[string]interface{}{
+			"errors": map[string]interface{}{
+				"items": map[string]interface{}{"one": "a", "few": "b", "other": "c"},
+			},
+		}, catalog, c.lang)
+	}
+
+	// zero and two forms have a number of their own
+	categories, err := formCategories("", PluralForms["ar"])
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"zero", "one", "two", "few", "many", "other"}, categories)
+
+	categories, err = formCategories("", "nplurals=1; plural=0;")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"other"}, categories)
+
+	_, err = formCategories("", "nplurals=2; plural=(n != 1;")
+	assert.EqualError(t, err, `invalid plural expression "(n != 1": missing ) at 7`)
+
+	_, err = formCategories("", "nplurals=2; plural=n;")
+	assert.EqualError(t, err, "plural form 2 of 2 is out of the 2 forms")
+}
+
+func TestPluralRoundTrip(t *testing.T) {
+
+	t.Parallel()
+
+	source := withItems(en.Messages, map[string]interface{}{"one": "One item", "other": "{count} items"})
+	for lang, catalog := range map[string]map[string]interface{}{
+		"en": source,
+		"fa": withItems(fa.Messages, map[string]interface{}{"one": "یک مورد", "other": "{count} مورد"}),
+		"ru": withItems(fa.Messages, map[string]interface{}{"one": "{count} предмет", "few": "{count} предмета", "other": "{count} предметов"}),
+	} {
+		var b bytes.Buffer
+		assert.NoError(t, Export(&b, lang, catalog, source))
+		assert.Contains(t, b.String(), "msgctxt \"errors.items\"\nmsgid \"One item\"\nmsgid_plural \"{count} items\"\nmsgstr[0] ", lang)
+		imported, err := Import(&b)
+		assert.NoError(t, err, lang)
+		assert.Equal(t, flat(t, catalog), flat(t, imported), lang)
+
+		b.Reset()
+		assert.NoError(t, Compile(&b, lang, catalog, source))
+		imported, err = ImportMO(&b)
+		assert.NoError(t, err, lang)
+		assert.Equal(t, flat(t, catalog), flat(t, imported), lang)
+	}
+
+	var b bytes.Buffer
+	assert.NoError(t, Export(&b, "ru", withItems(fa.Messages, map[string]interface{}{"one": "a", "few": "b", "other": "c"}), source))
+	assert.Contains(t, b.String(), "\"Plural-Forms: "+PluralForms["ru"]+"\\n\"\n")
+	assert.Contains(t, b.String(), "msgstr[0] \"a\"\nmsgstr[1] \"b\"\nmsgstr[2] \"c\"\n")
+
+	b.Reset()
+	assert.NoError(t, WriteTemplate(&b, source))
+	assert.Contains(t, b.String(), "msgid_plural \"{count} items\"\nmsgstr[0] \"\"\nmsgstr[1] \"\"\n")
+}
+
+func TestReadMOPlurals(t *testing.T) {
+
+	t.Parallel()
+
+	var b bytes.Buffer
+	assert.NoError(t, WriteMO(&b, "en", []*Entry{
+		{Context: "errors.items", ID: "One item\x00{count} items", Str: "One item\x00{count} items"},
+	}))
+	catalog, err := ImportMO(&b)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"errors": map[string]interface{}{
+			"items": map[string]interface{}{"one": "One item", "other": "{count} items"},
+		},
+	}, catalog)
+
+	b.Reset()
+	assert.NoError(t, WriteMO(&b, "xx", []*Entry{
+		{Context: "errors.items", ID: "item\x00items", Str: "a\x00b"},
+	}))
+	_, err = ReadMO(&b)
+	assert.EqualError(t, err, "gettext: errors.items: 2 plural forms do not match the plural categories other of xx")
+}
@@ -0,0 +1,186 @@
+package gettext
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+const (
+	moMagic = 0x950412de
+
+	// Separator of msgctxt and msgid in the keys of .mo files
+	contextSeparator = "\x04"
+)
+
+// Compile a catalog of a language to a .mo file
+//
+// @param w io.Writer, lang string, catalog map[string]interface{}, source map[string]interface{}
+// @return error
+func Compile(w io.Writer, lang string, catalog, source map[string]interface{}) error {
+	entries, err := Entries(catalog, source)
+	if err != nil {
+		return err
+	}
+	return WriteMO(w, lang, entries)
+}
+
+// Import a compiled .mo file as a catalog
+//
+// @param r io.Reader
+// @return (map[string]interface{}, error)
+func ImportMO(r io.Reader) (map[string]interface{}, error) {
+	entries, err := ReadMO(r)
+	if err != nil {
+		return nil, err
+	}
+	return Catalog(entries)
+}
+
+// Write entries as a .mo file, fuzzy entries are left out like msgfmt
+// does
+//
+// @param w io.Writer, lang string, entries []*Entry
+// @return error
+func WriteMO(w io.Writer, lang string, entries []*Entry) error {
+	header := "MIME-Version: 1.0\nContent-Type: text/plain; charset=UTF-8\nContent-Transfer-Encoding: 8bit\n"
+	if forms := pluralFormsOf(lang); lang != "" && forms != "" {
+		header = "Plural-Forms: " + forms + "\n" + header
+	}
+	if lang != "" {
+		header = "Language: " + lang + "\n" + header
+	}
+	type message struct{ id, str string }
+	messages := []message{{id: "", str: header}}
+	for _, entry := range entries {
+		if entry.Fuzzy || entry.Str == "" {
+			continue
+		}
+		id, str := entry.ID, entry.Str
+		// the forms of plural messages are separated by NUL
+		if entry.IDPlural != "" {
+			forms, err := entryForms(lang, entry)
+			if err != nil {
+				return err
+			}
+			id, str = id+"\x00"+entry.IDPlural, strings.Join(forms, "\x00")
+		}
+		if entry.Context != "" {
+			id = entry.Context + contextSeparator + id
+		}
+		messages = append(messages, message{id: id, str: str})
+	}
+	sort.Slice(messages, func(i, j int) bool {
+		return messages[i].id < messages[j].id
+	})
+
+	n := uint32(len(messages))
+	const headerSize = 28
+	var (
+		idsTable  = uint32(headerSize)
+		strsTable = idsTable + n*8
+		offset    = strsTable + n*8
+		tables    = make([]uint32, 0, n*4)
+		data      bytes.Buffer
+	)
+	for _, m := range messages {
+		tables = append(tables, uint32(len(m.id)), offset+uint32(data.Len()))
+		data.WriteString(m.id)
+		data.WriteByte(0)
+	}
+	for _, m := range messages {
+		tables = append(tables, uint32(len(m.str)), offset+uint32(data.Len()))
+		data.WriteString(m.str)
+		data.WriteByte(0)
+	}
+	for _, v := range []uint32{moMagic, 0, n, idsTable, strsTable, 0, offset} {
+		if err := binary.Write(w, binary.LittleEndian, v); err != nil {
+			return err
+		}
+	}
+	if err := binary.Write(w, binary.LittleEndian, tables); err != nil {
+		return err
+	}
+	_, err := w.Write(data.Bytes())
+	return err
+}
+
+// Read the entries of a .mo file, the header is left out. The forms of
+// plural messages are mapped to the plural categories like ReadPO does
+//
+// @param r io.Reader
+// @return ([]*Entry, error)
+func ReadMO(r io.Reader) ([]*Entry, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 28 {
+		return nil, errors.New("gettext: .mo file is too short")
+	}
+	var order binary.ByteOrder
+	switch {
+	case binary.LittleEndian.Uint32(data) == moMagic:
+		order = binary.LittleEndian
+	case binary.BigEndian.Uint32(data) == moMagic:
+		order = binary.BigEndian
+	default:
+		return nil, errors.New("gettext: invalid .mo magic number")
+	}
+	if revision := order.Uint32(data[4:]); revision>>16 != 0 {
+		return nil, fmt.Errorf("gettext: unsupported .mo revision %d", revision)
+	}
+	var (
+		n         = order.Uint32(data[8:])
+		idsTable  = order.Uint32(data[12:])
+		strsTable = order.Uint32(data[16:])
+	)
+	str := func(table, i uint32) (string, error) {
+		at := uint64(table) + uint64(i)*8
+		if at+8 > uint64(len(data)) {
+			return "", errors.New("gettext: .mo string table is out of range")
+		}
+		length, offset := uint64(order.Uint32(data[at:])), uint64(order.Uint32(data[at+4:]))
+		if offset+length > uint64(len(data)) {
+			return "", errors.New("gettext: .mo string is out of range")
+		}
+		return string(data[offset : offset+length]), nil
+	}
+	var lang, plural string
+	entries := make([]*Entry, 0, n)
+	for i := uint32(0); i < n; i++ {
+		id, err := str(idsTable, i)
+		if err != nil {
+			return nil, err
+		}
+		s, err := str(strsTable, i)
+		if err != nil {
+			return nil, err
+		}
+		if id == "" {
+			lang, plural = headerField(s, "Language"), headerField(s, "Plural-Forms")
+			continue
+		}
+		entry := &Entry{ID: id, Str: s}
+		if j := strings.Index(id, contextSeparator); j >= 0 {
+			entry.Context, entry.ID = id[:j], id[j+1:]
+		}
+		// the forms of plural translations are separated by NUL
+		if j := strings.IndexByte(entry.ID, 0); j >= 0 {
+			entry.ID, entry.IDPlural = entry.ID[:j], entry.ID[j+1:]
+			forms := strings.Split(entry.Str, "\x00")
+			entry.Str = forms[0]
+			if entry.Str != "" {
+				if entry.Plurals, err = pluralForms(lang, plural, forms); err != nil {
+					return nil, fmt.Errorf("gettext: %s: %v", entry.Context, err)
+				}
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
@@ -0,0 +1,389 @@
+package gettext
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	respond "github.com/mrjosh/respond.go"
+)
+
+// PluralForms are the Plural-Forms headers of the exported .po and .mo
+// files by language, regional tags like pt-BR use the header of their
+// base language when they have none of their own. The forms of languages
+// without a header are written in the order of respond.PluralCategories
+var PluralForms = map[string]string{
+	"ar": "nplurals=6; plural=(n==0 ? 0 : n==1 ? 1 : n==2 ? 2 : n%100>=3 && n%100<=10 ? 3 : n%100>=11 ? 4 : 5);",
+	"cs": "nplurals=3; plural=(n==1 ? 0 : n>=2 && n<=4 ? 1 : 2);",
+	"de": "nplurals=2; plural=(n != 1);",
+	"en": "nplurals=2; plural=(n != 1);",
+	"es": "nplurals=2; plural=(n != 1);",
+	"fa": "nplurals=2; plural=(n > 1);",
+	"fr": "nplurals=2; plural=(n > 1);",
+	"it": "nplurals=2; plural=(n != 1);",
+	"ja": "nplurals=1; plural=0;",
+	"ko": "nplurals=1; plural=0;",
+	"nl": "nplurals=2; plural=(n != 1);",
+	"pl": "nplurals=3; plural=(n==1 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);",
+	"pt": "nplurals=2; plural=(n != 1);",
+	"ru": "nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);",
+	"tr": "nplurals=2; plural=(n != 1);",
+	"uk": "nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);",
+	"zh": "nplurals=1; plural=0;",
+}
+
+// Get the Plural-Forms header of a language, empty when it has none
+func pluralFormsOf(lang string) string {
+	for tag := strings.ToLower(lang); tag != ""; {
+		if forms, ok := PluralForms[tag]; ok {
+			return forms
+		}
+		i := strings.LastIndexAny(tag, "-_")
+		if i < 0 {
+			break
+		}
+		tag = tag[:i]
+	}
+	return ""
+}
+
+// Get the CLDR plural categories of the msgstr[n] forms of a language.
+// With a Plural-Forms header every form is sampled with the numbers 0 to
+// 1000: the form of 1 is one, a form of 0 or 2 only is zero or two, the
+// last form is other and the forms between are few and many. Without a
+// header the forms are in the order of respond.PluralCategories
+func formCategories(lang, pluralForms string) ([]string, error) {
+	if pluralForms == "" {
+		if lang == "" {
+			return nil, fmt.Errorf("plural forms without a Plural-Forms or Language header")
+		}
+		return respond.PluralCategories(lang), nil
+	}
+	nplurals, plural, err := parsePluralForms(pluralForms)
+	if err != nil {
+		return nil, err
+	}
+	samples := make([][]int, nplurals)
+	for n := 0; n <= 1000; n++ {
+		i := plural(n)
+		if i < 0 || i >= nplurals {
+			return nil, fmt.Errorf("plural form %d of %d is out of the %d forms", i, n, nplurals)
+		}
+		samples[i] = append(samples[i], n)
+	}
+	categories := make([]string, nplurals)
+	between := []string{respond.PluralFew, respond.PluralMany}
+	for i, numbers := range samples {
+		switch {
+		case i == nplurals-1:
+			categories[i] = respond.PluralOther
+		case contains(numbers, 1):
+			categories[i] = respond.PluralOne
+		case len(numbers) == 1 && numbers[0] == 0:
+			categories[i] = respond.PluralZero
+		case len(numbers) == 1 && numbers[0] == 2:
+			categories[i] = respond.PluralTwo
+		case len(between) > 0:
+			categories[i], between = between[0], between[1:]
+		default:
+			return nil, fmt.Errorf("%d plural forms are more than the plural categories", nplurals)
+		}
+	}
+	return categories, nil
+}
+
+// Get the plural forms of a message by their CLDR categories, a language
+// whose whole numbers are never other, like ru without a Plural-Forms
+// header, uses the last form for other
+func pluralForms(lang, header string, forms []string) (map[string]string, error) {
+	categories, err := formCategories(lang, header)
+	if err != nil {
+		return nil, fmt.Errorf("%d %v", len(forms), err)
+	}
+	if len(forms) != len(categories) {
+		if header != "" {
+			return nil, fmt.Errorf("%d plural forms do not match the Plural-Forms header %q", len(forms), header)
+		}
+		return nil, fmt.Errorf("%d plural forms do not match the plural categories %s of %s",
+			len(forms), strings.Join(categories, ", "), lang)
+	}
+	plurals := make(map[string]string, len(forms)+1)
+	for i, category := range categories {
+		plurals[category] = forms[i]
+	}
+	if _, ok := plurals[respond.PluralOther]; !ok {
+		plurals[respond.PluralOther] = forms[len(forms)-1]
+	}
+	return plurals, nil
+}
+
+// Get the msgstr[n] forms of a plural entry in a language, the forms of
+// templates without a language are the one and other forms of english.
+// A category which the entry does not have uses its other form
+func entryForms(lang string, entry *Entry) ([]string, error) {
+	categories := []string{respond.PluralOne, respond.PluralOther}
+	if lang != "" {
+		var err error
+		if categories, err = formCategories(lang, pluralFormsOf(lang)); err != nil {
+			return nil, fmt.Errorf("gettext: %s: %v", lang, err)
+		}
+	}
+	forms := make([]string, len(categories))
+	for i, category := range categories {
+		form, ok := entry.Plurals[category]
+		if !ok {
+			form = entry.Plurals[respond.PluralOther]
+		}
+		forms[i] = form
+	}
+	return forms, nil
+}
+
+func contains(numbers []int, n int) bool {
+	for _, number := range numbers {
+		if number == n {
+			return true
+		}
+	}
+	return false
+}
+
+// Parse a Plural-Forms header like "nplurals=2; plural=(n != 1);"
+func parsePluralForms(header string) (int, pluralExpr, error) {
+	var (
+		nplurals   int
+		expression string
+	)
+	for _, part := range strings.Split(header, ";") {
+		i := strings.IndexByte(part, '=')
+		if i < 0 {
+			continue
+		}
+		switch strings.TrimSpace(part[:i]) {
+		case "nplurals":
+			n, err := strconv.Atoi(strings.TrimSpace(part[i+1:]))
+			if err != nil || n < 1 {
+				return 0, nil, fmt.Errorf("invalid nplurals in the Plural-Forms header %q", header)
+			}
+			nplurals = n
+		case "plural":
+			expression = part[i+1:]
+		}
+	}
+	if nplurals == 0 || strings.TrimSpace(expression) == "" {
+		return 0, nil, fmt.Errorf("invalid Plural-Forms header %q", header)
+	}
+	p := &pluralParser{input: expression}
+	plural, err := p.parse()
+	if err != nil {
+		return 0, nil, fmt.Errorf("invalid plural expression %q: %v", strings.TrimSpace(expression), err)
+	}
+	return nplurals, plural, nil
+}
+
+// pluralParser parses the C expressions of Plural-Forms headers, with
+// the variable n, numbers, parentheses and the arithmetic, comparison,
+// logical and conditional operators
+type pluralParser struct {
+	input string
+	pos   int
+}
+
+// pluralExpr gives the index of the plural form of a number
+type pluralExpr func(n int) int
+
+func (p *pluralParser) parse() (pluralExpr, error) {
+	expr, err := p.ternary()
+	if err != nil {
+		return nil, err
+	}
+	if p.skip(); p.pos < len(p.input) {
+		return nil, fmt.Errorf("unexpected %q", p.input[p.pos:])
+	}
+	return expr, nil
+}
+
+// condition ? a : b
+func (p *pluralParser) ternary() (pluralExpr, error) {
+	condition, err := p.binary(0)
+	if err != nil {
+		return nil, err
+	}
+	if !p.accept("?") {
+		return condition, nil
+	}
+	a, err := p.ternary()
+	if err != nil {
+		return nil, err
+	}
+	if !p.accept(":") {
+		return nil, fmt.Errorf("missing : at %d", p.pos)
+	}
+	b, err := p.ternary()
+	if err != nil {
+		return nil, err
+	}
+	return func(n int) int {
+		if condition(n) != 0 {
+			return a(n)
+		}
+		return b(n)
+	}, nil
+}
+
+// Binary operators by their precedence, from the lowest
+var pluralOperators = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!="},
+	{"<=", ">=", "<", ">"},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func (p *pluralParser) binary(level int) (pluralExpr, error) {
+	if level == len(pluralOperators) {
+		return p.unary()
+	}
+	left, err := p.binary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		operator := ""
+		for _, op := range pluralOperators[level] {
+			if p.accept(op) {
+				operator = op
+				break
+			}
+		}
+		if operator == "" {
+			return left, nil
+		}
+		right, err := p.binary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = pluralOperation(operator, left, right)
+	}
+}
+
+func pluralOperation(operator string, a, b pluralExpr) pluralExpr {
+	boolean := func(v bool) int {
+		if v {
+			return 1
+		}
+		return 0
+	}
+	return func(n int) int {
+		x := a(n)
+		switch operator {
+		case "||":
+			return boolean(x != 0 || b(n) != 0)
+		case "&&":
+			return boolean(x != 0 && b(n) != 0)
+		}
+		y := b(n)
+		switch operator {
+		case "==":
+			return boolean(x == y)
+		case "!=":
+			return boolean(x != y)
+		case "<=":
+			return boolean(x <= y)
+		case ">=":
+			return boolean(x >= y)
+		case "<":
+			return boolean(x < y)
+		case ">":
+			return boolean(x > y)
+		case "+":
+			return x + y
+		case "-":
+			return x - y
+		case "*":
+			return x * y
+		}
+		if y == 0 {
+			return 0
+		}
+		if operator == "/" {
+			return x / y
+		}
+		return x % y
+	}
+}
+
+func (p *pluralParser) unary() (pluralExpr, error) {
+	if p.accept("!") {
+		operand, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return func(n int) int {
+			if operand(n) == 0 {
+				return 1
+			}
+			return 0
+		}, nil
+	}
+	if p.accept("(") {
+		expr, err := p.ternary()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, fmt.Errorf("missing ) at %d", p.pos)
+		}
+		return expr, nil
+	}
+	if p.accept("n") {
+		return func(n int) int { return n }, nil
+	}
+	start := p.pos
+	for p.pos < len(p.input) && p.input[p.pos] >= '0' && p.input[p.pos] <= '9' {
+		p.pos++
+	}
+	if start == p.pos {
+		if p.pos == len(p.input) {
+			return nil, fmt.Errorf("unexpected end")
+		}
+		return nil, fmt.Errorf("unexpected %q", p.input[p.pos:])
+	}
+	value, err := strconv.Atoi(p.input[start:p.pos])
+	if err != nil {
+		return nil, err
+	}
+	return func(int) int { return value }, nil
+}
+
+// Skip the spaces and take a token when it is next
+func (p *pluralParser) accept(token string) bool {
+	p.skip()
+	if !strings.HasPrefix(p.input[p.pos:], token) {
+		return false
+	}
+	// a single operator is not the start of a longer one, like < of <=
+	if next := p.pos + len(token); len(token) == 1 && next < len(p.input) && isOperator(token+p.input[next:next+1]) {
+		return false
+	}
+	p.pos += len(token)
+	return true
+}
+
+func isOperator(token string) bool {
+	for _, operators := range pluralOperators {
+		for _, op := range operators {
+			if op == token {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (p *pluralParser) skip() {
+	for p.pos < len(p.input) && strings.ContainsRune(" \t\r\n", rune(p.input[p.pos])) {
+		p.pos++
+	}
+}
@@ -0,0 +1,253 @@
+package gettext
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Write a .pot template of the source catalog, every msgstr is empty
+//
+// @param w io.Writer, source map[string]interface{}
+// @return error
+func WriteTemplate(w io.Writer, source map[string]interface{}) error {
+	entries, err := Entries(source, source)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		entry.Str, entry.Plurals = "", nil
+	}
+	return WritePO(w, "", entries)
+}
+
+// Export a catalog of a language to a .po file, the msgid of the
+// entries are the strings of the source catalog
+//
+//	gettext.Export(file, "fa", fa.Messages, en.Messages)
+//
+// @param w io.Writer, lang string, catalog map[string]interface{}, source map[string]interface{}
+// @return error
+func Export(w io.Writer, lang string, catalog, source map[string]interface{}) error {
+	entries, err := Entries(catalog, source)
+	if err != nil {
+		return err
+	}
+	return WritePO(w, lang, entries)
+}
+
+// Import a translated .po file as a catalog
+//
+// @param r io.Reader
+// @return (map[string]interface{}, error)
+func Import(r io.Reader) (map[string]interface{}, error) {
+	entries, err := ReadPO(r)
+	if err != nil {
+		return nil, err
+	}
+	return Catalog(entries)
+}
+
+// Write entries as a .po file, the forms of plural entries are written
+// in the order of the PluralForms header of the language
+//
+// @param w io.Writer, lang string, entries []*Entry
+// @return error
+func WritePO(w io.Writer, lang string, entries []*Entry) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "msgid \"\"\nmsgstr \"\"\n")
+	if lang != "" {
+		fmt.Fprintf(bw, "%s\n", quote("Language: "+lang+"\n"))
+		if forms := pluralFormsOf(lang); forms != "" {
+			fmt.Fprintf(bw, "%s\n", quote("Plural-Forms: "+forms+"\n"))
+		}
+	}
+	fmt.Fprintf(bw, "%s\n", quote("MIME-Version: 1.0\n"))
+	fmt.Fprintf(bw, "%s\n", quote("Content-Type: text/plain; charset=UTF-8\n"))
+	fmt.Fprintf(bw, "%s\n", quote("Content-Transfer-Encoding: 8bit\n"))
+	for _, entry := range entries {
+		fmt.Fprintln(bw)
+		for _, comment := range entry.Comments {
+			fmt.Fprintf(bw, "#. %s\n", comment)
+		}
+		if entry.Fuzzy {
+			fmt.Fprintln(bw, "#, fuzzy")
+		}
+		fmt.Fprintf(bw, "msgctxt %s\n", quote(entry.Context))
+		fmt.Fprintf(bw, "msgid %s\n", quote(entry.ID))
+		if entry.IDPlural == "" {
+			fmt.Fprintf(bw, "msgstr %s\n", quote(entry.Str))
+			continue
+		}
+		forms, err := entryForms(lang, entry)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(bw, "msgid_plural %s\n", quote(entry.IDPlural))
+		for i, form := range forms {
+			fmt.Fprintf(bw, "msgstr[%d] %s\n", i, quote(form))
+		}
+	}
+	return bw.Flush()
+}
+
+// Read the entries of a .po file, the header and the obsolete entries
+// are left out. The msgstr[n] forms of plural messages are mapped to the
+// plural categories by the Plural-Forms of the header, or by the
+// Language of the header when it has no Plural-Forms
+//
+// @param r io.Reader
+// @return ([]*Entry, error)
+func ReadPO(r io.Reader) ([]*Entry, error) {
+	var (
+		entries []*Entry
+		entry   = &Entry{}
+		forms   []string
+		field   *string
+		hasStr  bool
+		lang    string
+		plural  string
+		line    int
+		start   int
+		scanner = bufio.NewScanner(r)
+	)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	flush := func() error {
+		if !hasStr {
+			return nil
+		}
+		e, f := entry, forms
+		entry, forms, field, hasStr = &Entry{}, nil, nil, false
+		if e.Context == "" && e.ID == "" {
+			lang, plural = headerField(e.Str, "Language"), headerField(e.Str, "Plural-Forms")
+			return nil
+		}
+		if len(f) > 0 {
+			e.Str = f[0]
+		}
+		if e.IDPlural != "" && e.Str != "" && !e.Fuzzy {
+			plurals, err := pluralForms(lang, plural, f)
+			if err != nil {
+				return fmt.Errorf("gettext: line %d: %v", start, err)
+			}
+			e.Plurals = plurals
+		}
+		entries = append(entries, e)
+		return nil
+	}
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		switch {
+		case text == "":
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			continue
+		case strings.HasPrefix(text, "#"):
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			field = nil
+			switch {
+			case strings.HasPrefix(text, "#,"):
+				for _, flag := range strings.Split(text[2:], ",") {
+					if strings.TrimSpace(flag) == "fuzzy" {
+						entry.Fuzzy = true
+					}
+				}
+			case strings.HasPrefix(text, "#."):
+				entry.Comments = append(entry.Comments, strings.TrimSpace(text[2:]))
+			}
+			continue
+		case strings.HasPrefix(text, `"`):
+			if field == nil {
+				return nil, fmt.Errorf("gettext: line %d: string without a keyword", line)
+			}
+			s, err := unquote(text)
+			if err != nil {
+				return nil, fmt.Errorf("gettext: line %d: %v", line, err)
+			}
+			*field += s
+			continue
+		}
+		i := strings.IndexAny(text, " \t")
+		if i < 0 {
+			return nil, fmt.Errorf("gettext: line %d: unexpected %q", line, text)
+		}
+		keyword, value := text[:i], strings.TrimSpace(text[i:])
+		switch {
+		case keyword == "msgctxt", keyword == "msgid":
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			if entry.Context == "" && entry.ID == "" {
+				start = line
+			}
+			field = &entry.ID
+			if keyword == "msgctxt" {
+				field = &entry.Context
+			}
+		case keyword == "msgid_plural":
+			field = &entry.IDPlural
+		case keyword == "msgstr":
+			field, hasStr = &entry.Str, true
+		case strings.HasPrefix(keyword, "msgstr[") && strings.HasSuffix(keyword, "]"):
+			n, err := strconv.Atoi(keyword[len("msgstr[") : len(keyword)-1])
+			if err != nil || n != len(forms) {
+				return nil, fmt.Errorf("gettext: line %d: unexpected %s", line, keyword)
+			}
+			forms = append(forms, "")
+			field, hasStr = &forms[n], true
+		default:
+			return nil, fmt.Errorf("gettext: line %d: unknown keyword %q", line, keyword)
+		}
+		s, err := unquote(value)
+		if err != nil {
+			return nil, fmt.Errorf("gettext: line %d: %v", line, err)
+		}
+		*field = s
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Quote a string as a .po string
+func quote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, c := range s {
+		switch c {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\r':
+			b.WriteString(`\r`)
+		default:
+			b.WriteRune(c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// Unquote a .po string, the escapes of .po files are the escapes of C
+// which strconv.Unquote understands
+func unquote(s string) (string, error) {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return "", fmt.Errorf("invalid string %s", s)
+	}
+	return strconv.Unquote(s)
+}
@@ -2,4 +2,8 @@ module github.com/mrjosh/respond.go
 
 go 1.16
 
-require github.com/stretchr/testify v1.7.0 // indirect
+require (
+	github.com/BurntSushi/toml v1.2.1
+	github.com/stretchr/testify v1.7.0
+	gopkg.in/yaml.v3 v3.0.1
+)
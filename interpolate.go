@@ -0,0 +1,208 @@
+package respond
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Args are the values of the named placeholders of a message, like
+// {field} and {count}. The count argument also picks the plural form
+//
+//      r.RequestFieldNotfound(respond.Args{"field": "email"})
+type Args map[string]interface{}
+
+// Merge a list of arguments, the later ones take precedence
+func mergeArgs(list []Args) Args {
+	switch len(list) {
+	case 0:
+		return nil
+	case 1:
+		return list[0]
+	}
+	args := Args{}
+	for _, a := range list {
+		for k, v := range a {
+			args[k] = v
+		}
+	}
+	return args
+}
+
+// Pick the message of a catalog entry, which is either a string or the
+// plural forms of the message picked by the count argument
+func resolveMessage(node interface{}, lang string, args Args) (string, bool) {
+	if message, ok := node.(string); ok {
+		return message, true
+	}
+	forms := levelOf(node)
+	if forms == nil || !isPluralForms(forms) {
+		return "", false
+	}
+	category := PluralOther
+	if n, ok := toFloat(args["count"]); ok {
+		category = pluralCategory(lang, n)
+	}
+	if message, ok := forms[category].(string); ok {
+		return message, true
+	}
+	message, ok := forms[PluralOther].(string)
+	return message, ok
+}
+
+// Replace the {name} placeholders of a message with its arguments,
+// numbers are formatted for the language. A placeholder without an
+// argument is dropped with the space around it, so optional arguments
+// keep the message readable
+func interpolate(message, lang string, args Args) string {
+	if !strings.Contains(message, "{") {
+		return message
+	}
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(message, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(message[start:], '}')
+		if end < 0 {
+			break
+		}
+		end += start
+		name := message[start+1 : end]
+		if !isPlaceholderName(name) {
+			b.WriteString(message[:end+1])
+			message = message[end+1:]
+			continue
+		}
+		b.WriteString(message[:start])
+		message = message[end+1:]
+		if v, ok := args[name]; ok {
+			b.WriteString(formatArg(v, lang))
+			continue
+		}
+		out := b.String()
+		switch {
+		case strings.HasSuffix(out, " ") && (message == "" || strings.ContainsAny(message[:1], " .,!?;:")):
+			b.Reset()
+			b.WriteString(out[:len(out)-1])
+		case out == "" && strings.HasPrefix(message, " "):
+			message = message[1:]
+		}
+	}
+	b.WriteString(message)
+	return b.String()
+}
+
+func isPlaceholderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range name {
+		if !(c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// Format an argument, numbers use Persian digits and separators in fa
+func formatArg(v interface{}, lang string) string {
+	var number string
+	switch value := v.(type) {
+	case string:
+		return value
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		number = fmt.Sprint(value)
+	case float32:
+		number = strconv.FormatFloat(float64(value), 'f', -1, 32)
+	case float64:
+		number = strconv.FormatFloat(value, 'f', -1, 64)
+	case json.Number:
+		number = value.String()
+	default:
+		return fmt.Sprint(v)
+	}
+	if baseTag(lang) == "fa" {
+		return persianNumber(number)
+	}
+	return number
+}
+
+// Convert a formatted number to Persian digits with the Persian
+// thousands and decimal separators
+func persianNumber(number string) string {
+	sign := ""
+	if strings.HasPrefix(number, "-") {
+		sign, number = "\u200e\u2212", number[1:]
+	}
+	integer, fraction := number, ""
+	if i := strings.IndexByte(number, '.'); i >= 0 {
+		integer, fraction = number[:i], number[i+1:]
+	}
+	var b strings.Builder
+	b.WriteString(sign)
+	for i, c := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			b.WriteRune('٬')
+		}
+		b.WriteRune(persianDigit(c))
+	}
+	if fraction != "" {
+		b.WriteRune('٫')
+		for _, c := range fraction {
+			b.WriteRune(persianDigit(c))
+		}
+	}
+	return b.String()
+}
+
+func persianDigit(c rune) rune {
+	if c >= '0' && c <= '9' {
+		return '۰' + (c - '0')
+	}
+	return c
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch value := v.(type) {
+	case int:
+		return float64(value), true
+	case int8:
+		return float64(value), true
+	case int16:
+		return float64(value), true
+	case int32:
+		return float64(value), true
+	case int64:
+		return float64(value), true
+	case uint:
+		return float64(value), true
+	case uint8:
+		return float64(value), true
+	case uint16:
+		return float64(value), true
+	case uint32:
+		return float64(value), true
+	case uint64:
+		return float64(value), true
+	case float32:
+		return float64(value), true
+	case float64:
+		return value, true
+	case json.Number:
+		f, err := value.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// Get the base language of a tag, fa for fa-IR
+func baseTag(tag string) string {
+	tag = strings.ToLower(tag)
+	if i := strings.IndexByte(tag, '-'); i >= 0 {
+		return tag[:i]
+	}
+	return tag
+}
@@ -0,0 +1,156 @@
+package respond
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterpolate(t *testing.T) {
+
+	t.Parallel()
+
+	cases := []struct {
+		message  string
+		lang     string
+		args     Args
+		expected string
+	}{
+		{"Requested field {field} is not found!", "en", Args{"field": "email"}, "Requested field email is not found!"},
+		{"Requested field {field} is not found!", "en", nil, "Requested field is not found!"},
+		{"Failed because of duplicate {field}", "en", nil, "Failed because of duplicate"},
+		{"{count} items", "en", Args{"count": 12500}, "12500 items"},
+		{"{count} مورد", "fa", Args{"count": 12500}, "۱۲٬۵۰۰ مورد"},
+		{"{count} مورد", "fa-IR", Args{"count": 2.75}, "۲٫۷۵ مورد"},
+		{"{count} مورد", "fa", Args{"count": -3}, "‎−۳ مورد"},
+		{"{name} {name}", "fa", Args{"name": "1001"}, "1001 1001"},
+		{"json {\"a\": 1} {x-y}", "en", nil, "json {\"a\": 1} {x-y}"},
+		{"unterminated {field", "en", nil, "unterminated {field"},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.expected, interpolate(c.message, c.lang, c.args), c.message)
+	}
+}
+
+func TestPluralCategory(t *testing.T) {
+
+	t.Parallel()
+
+	assert.Equal(t, PluralOne, pluralCategory("en", 1))
+	assert.Equal(t, PluralOther, pluralCategory("en", 0))
+	assert.Equal(t, PluralOther, pluralCategory("en-US", 2))
+	assert.Equal(t, PluralOne, pluralCategory("fa", 0))
+	assert.Equal(t, PluralOne, pluralCategory("fa-IR", 1))
+	assert.Equal(t, PluralOne, pluralCategory("fa", 0.5))
+	assert.Equal(t, PluralOther, pluralCategory("fa", 2))
+	assert.Equal(t, PluralOther, pluralCategory("xx", 1))
+
+	assert.Equal(t, []string{PluralOne, PluralOther}, PluralCategories("fa-IR"))
+	assert.Equal(t, []string{PluralOther}, PluralCategories("xx"))
+}
+
+func TestRequestFieldNotfoundArgs(t *testing.T) {
+
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+	NewWithWriter(recorder).RequestFieldNotfound(Args{"field": "email"})
+
+	expected, err := getExpectedMap(recorder.Body)
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"status":  "failed",
+		"error":   float64(1001),
+		"message": "Oops... Requested field email is not found!",
+	}, expected)
+}
+
+func TestRequestFieldDuplicatedArgsFa(t *testing.T) {
+
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+	NewWithWriter(recorder).Language("fa").RequestFieldDuplicated(Args{"field": "ایمیل"})
+
+	expected, err := getExpectedMap(recorder.Body)
+	assert.NoError(t, err)
+
+	assert.Equal(t, ".ورودی ایمیل مورد نظر تکراری است", expected["message"])
+}
+
+func TestPluralMessages(t *testing.T) {
+
+	t.Parallel()
+
+	catalog := map[string]interface{}{
+		"success": "success",
+		"failed":  "failed",
+		"errors": map[string]interface{}{
+			"success": map[string]interface{}{
+				"delete": map[string]interface{}{
+					"one":   "{count} item is deleted!",
+					"other": "{count} items are deleted!",
+				},
+			},
+		},
+	}
+
+	for _, c := range []struct {
+		lang     string
+		count    interface{}
+		expected string
+	}{
+		{"en-XX", 1, "1 item is deleted!"},
+		{"en-XX", 3, "3 items are deleted!"},
+		{"en-XX", nil, "items are deleted!"},
+		{"fa-XX", 0, "۰ item is deleted!"},
+		{"fa-XX", 1500, "۱٬۵۰۰ items are deleted!"},
+	} {
+		var (
+			recorder = httptest.NewRecorder()
+			respond  = NewWithWriter(recorder)
+		)
+		respond.Messages().AddLanguageTranslation(c.lang, catalog)
+		respond.Messages().SetFallback(c.lang)
+		args := Args{}
+		if c.count != nil {
+			args["count"] = c.count
+		}
+		assert.NoError(t, respond.Language(c.lang).DeleteSucceeded(args))
+
+		expected, err := getExpectedMap(recorder.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, c.expected, expected["message"], c.lang)
+	}
+}
+
+func TestValidatePluralForms(t *testing.T) {
+
+	t.Parallel()
+
+	errs := ValidateCatalog("xx.json", map[string]interface{}{
+		"success": "success",
+		"failed":  "failed",
+		"errors": map[string]interface{}{
+			"success": map[string]interface{}{"insert": "a", "delete": "b", "update": "c"},
+			"failed":  map[string]interface{}{"insert": "a", "delete": "b", "update": "c"},
+			"1001": map[string]interface{}{
+				"message": map[string]interface{}{"one": "a", "other": "b"},
+			},
+			"1002": map[string]interface{}{
+				"message": map[string]interface{}{"one": "a"},
+			},
+			"1003": map[string]interface{}{
+				"message": map[string]interface{}{"one": 1, "other": "b"},
+			},
+		},
+	})
+
+	assert.Equal(t, SchemaErrors{
+		{File: "xx.json", Key: "errors.1002.message", Message: "must be plural forms with an other form"},
+		{File: "xx.json", Key: "errors.1003.message.one", Message: "must be a string, got number"},
+	}, errs)
+}
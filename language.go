@@ -0,0 +1,128 @@
+package respond
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultLanguage is used when the Accept-Language header of a request
+// does not match any of the registered translations
+var DefaultLanguage = "en"
+
+// New respond type with custom writer which negotiates the language of
+// responses from the Accept-Language header of the request
+//
+// @param w http.ResponseWriter, req *http.Request
+// @return *Respond
+func NewWithRequest(w http.ResponseWriter, req *http.Request) *Respond {
+	r := NewWithWriter(w)
+	r.request = req
+	return r
+}
+
+// Pick the best language from the value of an Accept-Language header
+//
+// Tags are ordered by their q-values and a regional tag like fa-IR is
+// matched against the base fa catalog when there is no exact match,
+// unless fa is excluded with q=0. The fallback is returned when nothing
+// in the header is acceptable.
+//
+// @param header string, available []string, fallback string
+// @return string
+func NegotiateLanguage(header string, available []string, fallback string) string {
+	supported := make(map[string]string, len(available))
+	for _, lang := range available {
+		supported[strings.ToLower(lang)] = lang
+	}
+	return negotiateLanguage(header, supported, fallback)
+}
+
+// Pick the best language of the lower case tags of the supported
+// languages
+func negotiateLanguage(header string, supported map[string]string, fallback string) string {
+	if header == "" {
+		return fallback
+	}
+	tags, excluded := parseAcceptLanguage(header)
+	for _, tag := range tags {
+		if tag == "*" {
+			break
+		}
+		// the base languages which are excluded with q=0 are skipped
+		for candidate := tag; candidate != ""; candidate = parentTag(candidate) {
+			if lang, ok := supported[candidate]; ok && !excluded[candidate] {
+				return lang
+			}
+		}
+	}
+	return fallback
+}
+
+type weightedTag struct {
+	tag string
+	q   float64
+}
+
+// Parse Accept-Language header into tags ordered by preference, the
+// tags with q=0 are not acceptable and are returned as excluded
+func parseAcceptLanguage(header string) ([]string, map[string]bool) {
+	var (
+		tags     []string
+		excluded = map[string]bool{}
+	)
+	for _, w := range parseQualityValues(header) {
+		tag := strings.Replace(w.tag, "_", "-", -1)
+		if w.q > 0 {
+			tags = append(tags, tag)
+		} else {
+			excluded[tag] = true
+		}
+	}
+	return tags, excluded
+}
+
+// Parse a comma separated list of lowercased tokens with optional
+// q-values, as used by the Accept and Accept-Language headers. The
+// tokens are ordered by their q-values, the ones with q=0 are last and
+// are not acceptable
+func parseQualityValues(header string) []weightedTag {
+	var weighted []weightedTag
+	for _, part := range strings.Split(header, ",") {
+		params := strings.Split(part, ";")
+		tag := strings.ToLower(strings.TrimSpace(params[0]))
+		if tag == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range params[1:] {
+			i := strings.IndexByte(param, '=')
+			if i < 0 || !strings.EqualFold(strings.TrimSpace(param[:i]), "q") {
+				continue
+			}
+			v, err := strconv.ParseFloat(strings.TrimSpace(param[i+1:]), 64)
+			if err != nil {
+				v = 0
+			}
+			q = v
+		}
+		if q < 0 {
+			q = 0
+		}
+		weighted = append(weighted, weightedTag{tag: tag, q: q})
+	}
+	sort.SliceStable(weighted, func(i, j int) bool {
+		return weighted[i].q > weighted[j].q
+	})
+	return weighted
+}
+
+// Strip the last subtag of a language tag, fa-IR becomes fa
+func parentTag(tag string) string {
+	i := strings.LastIndex(tag, "-")
+	if i < 0 {
+		return ""
+	}
+	return tag[:i]
+}
@@ -0,0 +1,77 @@
+package respond
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiateLanguage(t *testing.T) {
+
+	t.Parallel()
+
+	available := []string{"en", "fa"}
+
+	cases := []struct {
+		header   string
+		expected string
+	}{
+		{"", "en"},
+		{"fa", "fa"},
+		{"fa-IR", "fa"},
+		{"FA-ir,en;q=0.5", "fa"},
+		{"en;q=0.4, fa;q=0.9", "fa"},
+		{"de-DE, fa;q=0.2", "fa"},
+		{"de, fr;q=0.8", "en"},
+		{"fa;q=0, en", "en"},
+		{"*", "en"},
+		{"fa;q=oops, en;q=0.1", "en"},
+		{"fa-IR, fa;q=0", "en"},
+		{"fa-IR, fa;q=0, de;q=0.5", "en"},
+		{"en;Q=0.5, fa;Q=0.9", "fa"},
+		{"fa; Q = 0, en", "en"},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.expected, NegotiateLanguage(c.header, available, "en"), c.header)
+	}
+}
+
+func TestNewWithRequest(t *testing.T) {
+
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("Accept-Language", "fa-IR,fa;q=0.9,en-US;q=0.8")
+
+	NewWithRequest(recorder, request).NotFound()
+
+	assert.Equal(t, http.StatusNotFound, recorder.Result().StatusCode)
+
+	expected, err := getExpectedMap(recorder.Body)
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"status":  "نا موفق",
+		"error":   float64(5404),
+		"message": ".صفحه درخواست شده پیدا نمیشود",
+	}, expected)
+}
+
+func TestNewWithRequestExplicitLanguage(t *testing.T) {
+
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("Accept-Language", "fa")
+
+	NewWithRequest(recorder, request).Language("en").NotFound()
+
+	expected, err := getExpectedMap(recorder.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, "failed", expected["status"])
+}
@@ -0,0 +1,248 @@
+package respond
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Kinds of the catalog lint issues
+const (
+	// A key of the reference language is missing from a language
+	LintMissing = "missing"
+
+	// A language has a key which is not in the reference language
+	LintExtra = "extra"
+
+	// A message is an empty string
+	LintEmpty = "empty"
+
+	// The cat, short or type of an error differs from the reference
+	// language or from the registered error. A language which leaves
+	// them out inherits them from the reference language
+	LintMetadata = "metadata"
+
+	// The placeholders of a message differ from the reference language
+	LintPlaceholder = "placeholder"
+
+	// A registered error code has no message in a language
+	LintMissingCode = "missing-code"
+
+	// An error code of the catalog is not registered
+	LintUnusedCode = "unused-code"
+
+	// Different messages of the reference language share the same
+	// translation, which is usually a generic text copied around
+	LintDuplicate = "duplicate"
+)
+
+// LintIssue is a problem found by LintCatalogs
+type LintIssue struct {
+	Kind    string
+	Lang    string
+	Key     string
+	Message string
+}
+
+func (i *LintIssue) String() string {
+	return fmt.Sprintf("%s: %s: %s: %s", i.Lang, i.Key, i.Kind, i.Message)
+}
+
+// Compare every catalog with the catalog of the reference language and
+// the registered errors. The issues are sorted by language and key
+//
+//      issues := respond.LintCatalogs(respond.NewMessages().Languages(), "en")
+//
+// @param catalogs map[string]map[string]interface{}, reference string
+// @return []*LintIssue
+func LintCatalogs(catalogs map[string]map[string]interface{}, reference string) []*LintIssue {
+	var issues []*LintIssue
+	report := func(kind, lang, key, format string, args ...interface{}) {
+		issues = append(issues, &LintIssue{Kind: kind, Lang: lang, Key: key, Message: fmt.Sprintf(format, args...)})
+	}
+
+	flat := make(map[string]map[string]interface{}, len(catalogs))
+	for lang, catalog := range catalogs {
+		flat[lang] = map[string]interface{}{}
+		flattenCatalog("", catalog, flat[lang])
+	}
+	ref, ok := flat[reference]
+	if !ok {
+		report(LintMissing, reference, "", "reference language has no catalog")
+		return issues
+	}
+
+	for lang, entries := range flat {
+		for _, key := range sortedKeys(entries) {
+			value := entries[key]
+			if lintMessages(value, func(message string) bool { return strings.TrimSpace(message) == "" }) {
+				report(LintEmpty, lang, key, "message is empty")
+			}
+			if lang == reference {
+				continue
+			}
+			refValue, ok := ref[key]
+			if !ok {
+				report(LintExtra, lang, key, "key is not in %s", reference)
+				continue
+			}
+			if isMetadataKey(key) {
+				if fmt.Sprint(value) != fmt.Sprint(refValue) {
+					report(LintMetadata, lang, key, "%q differs from %q in %s", value, refValue, reference)
+				}
+				continue
+			}
+			want, got := placeholdersOf(refValue), placeholdersOf(value)
+			if strings.Join(want, ",") != strings.Join(got, ",") {
+				report(LintPlaceholder, lang, key, "placeholders {%s} differ from {%s} in %s",
+					strings.Join(got, "}, {"), strings.Join(want, "}, {"), reference)
+			}
+		}
+		if lang == reference {
+			continue
+		}
+		for _, key := range sortedKeys(ref) {
+			if _, ok := entries[key]; !ok && !isMetadataKey(key) {
+				report(LintMissing, lang, key, "key of %s is missing", reference)
+			}
+		}
+		lintDuplicates(lang, entries, ref, report)
+	}
+
+	errorRegistry.RLock()
+	registered := make(map[string]*Error, len(errorRegistry.codes))
+	for code, e := range errorRegistry.codes {
+		registered[strconv.Itoa(code)] = e
+	}
+	errorRegistry.RUnlock()
+
+	for _, code := range sortedKeys(levelOf(lookupPath(catalogs[reference], []string{"errors"}))) {
+		if code == "success" || code == "failed" {
+			continue
+		}
+		e, ok := registered[code]
+		if !ok {
+			report(LintUnusedCode, reference, "errors."+code, "error code is not registered")
+			continue
+		}
+		for field, want := range map[string]string{"cat": e.Cat, "short": e.Short} {
+			key := "errors." + code + "." + field
+			got, _ := ref[key].(string)
+			if want != "" && got != want {
+				report(LintMetadata, reference, key, "%q differs from the registered %q", got, want)
+			}
+		}
+	}
+	for code, e := range registered {
+		if _, ok := ref[e.Key]; ok {
+			// the other languages report it as a missing key
+			continue
+		}
+		for lang, entries := range flat {
+			if _, ok := entries[e.Key]; !ok {
+				report(LintMissingCode, lang, "errors."+code, "registered error %s has no message", e.Short)
+			}
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+		if a.Lang != b.Lang {
+			return a.Lang < b.Lang
+		}
+		if a.Key != b.Key {
+			return a.Key < b.Key
+		}
+		return a.Kind < b.Kind
+	})
+	return issues
+}
+
+// Report the messages of a language which share a translation while
+// their reference messages are different
+func lintDuplicates(lang string, entries, ref map[string]interface{}, report func(kind, lang, key, format string, args ...interface{})) {
+	first := map[string]string{}
+	for _, key := range sortedKeys(entries) {
+		message, ok := entries[key].(string)
+		if !ok || isMetadataKey(key) || strings.TrimSpace(message) == "" {
+			continue
+		}
+		other, ok := first[message]
+		if !ok {
+			first[message] = key
+			continue
+		}
+		if ref[key] != nil && ref[other] != nil && fmt.Sprint(ref[key]) != fmt.Sprint(ref[other]) {
+			report(LintDuplicate, lang, key, "same translation as %s", other)
+		}
+	}
+}
+
+// Flatten a catalog to its dotted keys, plural forms are kept as a
+// single value
+func flattenCatalog(prefix string, node interface{}, out map[string]interface{}) {
+	level := levelOf(node)
+	if level == nil || isPluralForms(level) {
+		out[prefix] = node
+		return
+	}
+	for key, value := range level {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		flattenCatalog(key, value, out)
+	}
+}
+
+// Whether a message or any of its plural forms matches
+func lintMessages(value interface{}, match func(message string) bool) bool {
+	if message, ok := value.(string); ok {
+		return match(message)
+	}
+	for _, form := range levelOf(value) {
+		if message, ok := form.(string); ok && match(message) {
+			return true
+		}
+	}
+	return false
+}
+
+// Get the sorted names of the placeholders of a message or of all its
+// plural forms
+func placeholdersOf(value interface{}) []string {
+	names := map[string]bool{}
+	lintMessages(value, func(message string) bool {
+		for {
+			start := strings.IndexByte(message, '{')
+			if start < 0 {
+				return false
+			}
+			end := strings.IndexByte(message[start:], '}')
+			if end < 0 {
+				return false
+			}
+			if name := message[start+1 : start+end]; isPlaceholderName(name) {
+				names[name] = true
+			}
+			message = message[start+end+1:]
+		}
+	})
+	list := make([]string, 0, len(names))
+	for name := range names {
+		list = append(list, name)
+	}
+	sort.Strings(list)
+	return list
+}
+
+func isMetadataKey(key string) bool {
+	if !strings.HasPrefix(key, "errors.") {
+		return false
+	}
+	switch key[strings.LastIndexByte(key, '.')+1:] {
+	case "cat", "short", "type":
+		return true
+	}
+	return false
+}
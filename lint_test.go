@@ -0,0 +1,141 @@
+package respond
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mrjosh/respond.go/translations/en"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLintBuiltinCatalogs(t *testing.T) {
+
+	t.Parallel()
+
+	assert.Empty(t, LintCatalogs(NewMessages().Languages(), "en"))
+}
+
+func TestLintCatalogs(t *testing.T) {
+
+	t.Parallel()
+
+	de := map[string]interface{}{
+		"success": "Erfolg",
+		"failed":  "",
+		"extra":   "zusätzlich",
+		"errors": map[string]interface{}{
+			"1001": map[string]interface{}{
+				"message": "Feld {name} nicht gefunden",
+				"type":    "error",
+				"short":   "field-missing",
+			},
+			"1002": map[string]interface{}{
+				"message": "Ungültig",
+				"type":    "error",
+				"short":   "user-not-found",
+			},
+			"1003": map[string]interface{}{
+				"message": "Ungültig",
+				"type":    "error",
+				"short":   "client-type-missing",
+			},
+		},
+	}
+
+	var issues []string
+	for _, issue := range LintCatalogs(map[string]map[string]interface{}{"en": en.Messages, "de": de}, "en") {
+		if issue.Kind == LintMissing {
+			continue
+		}
+		issues = append(issues, issue.String())
+	}
+	assert.Equal(t, []string{
+		`de: errors.1001.message: placeholder: placeholders {name} differ from {field} in en`,
+		`de: errors.1001.short: metadata: "field-missing" differs from "field-not-found" in en`,
+		`de: errors.1003.message: duplicate: same translation as errors.1002.message`,
+		`de: extra: extra: key is not in en`,
+		`de: failed: empty: message is empty`,
+	}, issues)
+}
+
+func TestLintInheritedMetadata(t *testing.T) {
+
+	t.Parallel()
+
+	de := map[string]interface{}{
+		"success": "Erfolg",
+		"failed":  "Fehlgeschlagen",
+		"errors": map[string]interface{}{
+			"1001": map[string]interface{}{"message": "Feld {field} nicht gefunden"},
+		},
+	}
+
+	var keys []string
+	for _, issue := range LintCatalogs(map[string]map[string]interface{}{"en": en.Messages, "de": de}, "en") {
+		if issue.Lang == "de" && strings.HasPrefix(issue.Key, "errors.1001.") {
+			keys = append(keys, issue.Key)
+		}
+	}
+	// the type, short and cat of the error are inherited from en
+	assert.Empty(t, keys)
+}
+
+func TestLintRegisteredCodes(t *testing.T) {
+
+	t.Parallel()
+
+	catalog := map[string]interface{}{
+		"success": "success",
+		"failed":  "failed",
+		"errors": map[string]interface{}{
+			"9001": map[string]interface{}{"message": "Unknown"},
+			"5404": map[string]interface{}{
+				"message": "Not found",
+				"short":   "missing-page",
+			},
+		},
+	}
+
+	var issues []string
+	for _, issue := range LintCatalogs(map[string]map[string]interface{}{"xx": catalog}, "xx") {
+		switch issue.Key {
+		case "errors.5401", "errors.5404.short", "errors.9001":
+			issues = append(issues, issue.String())
+		}
+	}
+	assert.Equal(t, []string{
+		`xx: errors.5401: missing-code: registered error unauthorized has no message`,
+		`xx: errors.5404.short: metadata: "missing-page" differs from the registered "not-found"`,
+		`xx: errors.9001: unused-code: error code is not registered`,
+	}, issues)
+}
+
+func TestLintPluralPlaceholders(t *testing.T) {
+
+	t.Parallel()
+
+	plural := func(one, other string) map[string]interface{} {
+		return map[string]interface{}{
+			"success": "success",
+			"errors": map[string]interface{}{
+				"items": map[string]interface{}{"one": one, "other": other},
+			},
+		}
+	}
+
+	var issues []*LintIssue
+	for _, issue := range LintCatalogs(map[string]map[string]interface{}{
+		"en": plural("one item", "{count} items"),
+		"fa": plural("{count} مورد", "{count} مورد"),
+		"ru": plural("{count} предмет", "{number} предметов"),
+	}, "en") {
+		if issue.Kind == LintPlaceholder {
+			issues = append(issues, issue)
+		}
+	}
+
+	if assert.Len(t, issues, 1) {
+		assert.Equal(t, "ru", issues[0].Lang)
+		assert.Equal(t, "errors.items", issues[0].Key)
+	}
+}
@@ -0,0 +1,299 @@
+package respond
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// SchemaError is a problem of a catalog file
+type SchemaError struct {
+	File    string
+	Key     string
+	Message string
+}
+
+func (e *SchemaError) Error() string {
+	if e.Key == "" {
+		return fmt.Sprintf("respond: %s: %s", e.File, e.Message)
+	}
+	return fmt.Sprintf("respond: %s: %s: %s", e.File, e.Key, e.Message)
+}
+
+// SchemaErrors holds every problem of the loaded catalog files
+type SchemaErrors []*SchemaError
+
+func (e SchemaErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Parse a catalog file, the format is picked from the extension of the
+// name which is one of .json, .yaml, .yml or .toml
+//
+// @param name string, data []byte
+// @return (map[string]interface{}, error)
+func ParseCatalog(name string, data []byte) (map[string]interface{}, error) {
+	var (
+		decoded interface{}
+		err     error
+	)
+	switch strings.ToLower(path.Ext(name)) {
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		err = decoder.Decode(&decoded)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &decoded)
+	case ".toml":
+		var table map[string]interface{}
+		_, err = toml.Decode(string(data), &table)
+		decoded = table
+	default:
+		return nil, &SchemaError{File: name, Message: "unsupported catalog format"}
+	}
+	if err != nil {
+		return nil, &SchemaError{File: name, Message: err.Error()}
+	}
+	catalog, ok := normalizeCatalog(decoded).(map[string]interface{})
+	if !ok {
+		return nil, &SchemaError{File: name, Message: "catalog must be a map"}
+	}
+	if errs := ValidateCatalog(name, catalog); len(errs) > 0 {
+		return nil, errs
+	}
+	return catalog, nil
+}
+
+// Load a catalog file
+//
+// @param name string
+// @return (map[string]interface{}, error)
+func LoadCatalogFile(name string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCatalog(name, data)
+}
+
+// Load every catalog file of a directory of a file system like embed.FS,
+// the language of a catalog is the name of its file without the
+// extension, like fa-IR for fa-IR.yaml
+//
+//      //go:embed translations
+//      var translations embed.FS
+//
+//      catalogs, err := respond.LoadCatalogFS(translations, "translations")
+//
+// @param fsys fs.FS, dir string
+// @return (map[string]map[string]interface{}, error)
+func LoadCatalogFS(fsys fs.FS, dir string) (map[string]map[string]interface{}, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		catalogs = map[string]map[string]interface{}{}
+		errs     SchemaErrors
+	)
+	for _, entry := range entries {
+		ext := strings.ToLower(path.Ext(entry.Name()))
+		if entry.IsDir() || !isCatalogExt(ext) {
+			continue
+		}
+		name := path.Join(dir, entry.Name())
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		lang := strings.TrimSuffix(entry.Name(), path.Ext(entry.Name()))
+		if _, ok := catalogs[lang]; ok {
+			errs = append(errs, &SchemaError{File: name, Message: "duplicate catalog of language " + lang})
+			continue
+		}
+		catalog, err := ParseCatalog(name, data)
+		switch e := err.(type) {
+		case nil:
+			catalogs[lang] = catalog
+		case SchemaErrors:
+			errs = append(errs, e...)
+		case *SchemaError:
+			errs = append(errs, e)
+		default:
+			return nil, err
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return catalogs, nil
+}
+
+// Load every catalog file of a directory
+//
+// @param dir string
+// @return (map[string]map[string]interface{}, error)
+func LoadCatalogDir(dir string) (map[string]map[string]interface{}, error) {
+	return LoadCatalogFS(os.DirFS(dir), ".")
+}
+
+// Add every catalog file of a directory of a file system as a language
+// translation, nothing is added when a file is invalid
+//
+// @param fsys fs.FS, dir string
+// @return error
+func (m *Messages) LoadFS(fsys fs.FS, dir string) error {
+	catalogs, err := LoadCatalogFS(fsys, dir)
+	if err != nil {
+		return err
+	}
+	for lang, catalog := range catalogs {
+		m.AddLanguageTranslation(lang, catalog)
+	}
+	return nil
+}
+
+// Add every catalog file of a directory as a language translation
+//
+// @param dir string
+// @return error
+func (m *Messages) LoadDir(dir string) error {
+	return m.LoadFS(os.DirFS(dir), ".")
+}
+
+// Validate the structure of a catalog, success and failed must be
+// strings and errors must hold the success and failed messages of the
+// insert, delete and update actions and a message for every code
+//
+// @param file string, catalog map[string]interface{}
+// @return SchemaErrors
+func ValidateCatalog(file string, catalog map[string]interface{}) SchemaErrors {
+	var errs SchemaErrors
+	report := func(key, format string, args ...interface{}) {
+		errs = append(errs, &SchemaError{File: file, Key: key, Message: fmt.Sprintf(format, args...)})
+	}
+	for _, key := range []string{"success", "failed"} {
+		switch v := catalog[key].(type) {
+		case nil:
+			report(key, "is required")
+		case string:
+		default:
+			report(key, "must be a string, got %s", typeName(v))
+		}
+	}
+	errorsNode, ok := catalog["errors"]
+	if !ok {
+		report("errors", "is required")
+		return errs
+	}
+	entries := levelOf(errorsNode)
+	if entries == nil {
+		report("errors", "must be a map, got %s", typeName(errorsNode))
+		return errs
+	}
+	for _, action := range []string{"success", "failed"} {
+		if _, ok := entries[action]; !ok {
+			report("errors."+action, "is required")
+		}
+	}
+	for _, code := range sortedKeys(entries) {
+		key := "errors." + code
+		fields := levelOf(entries[code])
+		if fields == nil {
+			report(key, "must be a map, got %s", typeName(entries[code]))
+			continue
+		}
+		switch code {
+		case "success", "failed":
+			for _, action := range []string{"insert", "delete", "update"} {
+				if _, ok := fields[action]; !ok {
+					report(key+"."+action, "is required")
+				}
+			}
+		default:
+			if _, err := strconv.Atoi(code); err != nil {
+				report(key, "is not a numeric error code")
+			}
+			if _, ok := fields["message"]; !ok {
+				report(key+".message", "is required")
+			}
+		}
+		for _, field := range sortedKeys(fields) {
+			switch v := fields[field].(type) {
+			case string:
+			case map[string]interface{}:
+				if !isPluralForms(v) {
+					report(key+"."+field, "must be plural forms with an other form")
+					continue
+				}
+				for _, category := range sortedKeys(v) {
+					if _, ok := v[category].(string); !ok {
+						report(key+"."+field+"."+category, "must be a string, got %s", typeName(v[category]))
+					}
+				}
+			default:
+				report(key+"."+field, "must be a string, got %s", typeName(v))
+			}
+		}
+	}
+	return errs
+}
+
+func isCatalogExt(ext string) bool {
+	switch ext {
+	case ".json", ".yaml", ".yml", ".toml":
+		return true
+	}
+	return false
+}
+
+// Convert the maps of decoded catalogs to map[string]interface{}, YAML
+// decodes maps with numeric keys like 5404 to map[interface{}]interface{}
+func normalizeCatalog(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for k, item := range value {
+			value[k] = normalizeCatalog(item)
+		}
+		return value
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(value))
+		for k, item := range value {
+			m[fmt.Sprint(k)] = normalizeCatalog(item)
+		}
+		return m
+	case []interface{}:
+		for i, item := range value {
+			value[i] = normalizeCatalog(item)
+		}
+	}
+	return v
+}
+
+func typeName(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case []interface{}:
+		return "list"
+	case map[string]interface{}, map[interface{}]interface{}:
+		return "map"
+	}
+	return "number"
+}
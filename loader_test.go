@@ -0,0 +1,105 @@
+package respond
+
+import (
+	"embed"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//go:embed testdata/catalogs
+var testCatalogs embed.FS
+
+func TestLoadCatalogFS(t *testing.T) {
+
+	t.Parallel()
+
+	catalogs, err := LoadCatalogFS(testCatalogs, "testdata/catalogs")
+	assert.NoError(t, err)
+	assert.Len(t, catalogs, 3)
+
+	for lang, message := range map[string]string{
+		"de": "Hoppla... Die angeforderte Seite wurde nicht gefunden!",
+		"ru": "Упс ... Запрошенная страница не найдена!",
+		"es": "¡Ups... La página solicitada no se encontró!",
+	} {
+		assert.Equal(t, message, lookupPath(catalogs[lang], []string{"errors", "5404", "message"}), lang)
+	}
+}
+
+func TestMessagesLoadDir(t *testing.T) {
+
+	t.Parallel()
+
+	var (
+		recorder = httptest.NewRecorder()
+		respond  = NewWithWriter(recorder)
+	)
+
+	assert.NoError(t, respond.Messages().LoadDir("testdata/catalogs"))
+	assert.NoError(t, respond.Language("ru").NotFound())
+
+	assert.Equal(t, http.StatusNotFound, recorder.Result().StatusCode)
+
+	expected, err := getExpectedMap(recorder.Body)
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"status":  "не смогли",
+		"error":   float64(5404),
+		"message": "Упс ... Запрошенная страница не найдена!",
+	}, expected)
+}
+
+func TestLoadCatalogFile(t *testing.T) {
+
+	t.Parallel()
+
+	catalog, err := LoadCatalogFile("testdata/catalogs/es.toml")
+	assert.NoError(t, err)
+	assert.Equal(t, "éxito", catalog["success"])
+
+	_, err = LoadCatalogFile("testdata/catalogs/README.txt")
+	assert.EqualError(t, err, "respond: testdata/catalogs/README.txt: unsupported catalog format")
+}
+
+func TestLoadCatalogSchemaErrors(t *testing.T) {
+
+	t.Parallel()
+
+	_, err := LoadCatalogDir("testdata/invalid")
+
+	var errs SchemaErrors
+	assert.True(t, errors.As(err, &errs))
+	assert.Equal(t, SchemaErrors{
+		{File: "it.json", Key: "failed", Message: "is required"},
+		{File: "it.json", Key: "errors.failed", Message: "is required"},
+		{File: "it.json", Key: "errors.5404.message", Message: "is required"},
+		{File: "it.json", Key: "errors.5405.message", Message: "must be a string, got number"},
+		{File: "it.json", Key: "errors.oops", Message: "is not a numeric error code"},
+		{File: "it.json", Key: "errors.success.update", Message: "is required"},
+		{File: "pt.yaml", Key: "failed", Message: "must be a string, got list"},
+		{File: "pt.yaml", Key: "errors", Message: "is required"},
+	}, errs)
+}
+
+func TestParseCatalogSyntaxError(t *testing.T) {
+
+	t.Parallel()
+
+	_, err := ParseCatalog("fa.json", []byte(`{"success": `))
+	assert.EqualError(t, err, "respond: fa.json: unexpected EOF")
+}
+
+func TestValidateBuiltinCatalogs(t *testing.T) {
+
+	t.Parallel()
+
+	messages := NewMessages()
+	for lang, catalog := range messages.Languages() {
+		assert.Empty(t, ValidateCatalog(lang, catalog), lang)
+	}
+}
@@ -1,47 +1,493 @@
 package respond
 
 import (
+	"fmt"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/mrjosh/respond.go/translations/en"
 	"github.com/mrjosh/respond.go/translations/fa"
 )
 
+// What to do when a message key is missing from every language of the
+// fallback chain
+type MissingPolicy int
+
+const (
+	// Use the default language and then the key itself as the message
+	MissingUseDefault MissingPolicy = iota
+
+	// Use the key itself as the message
+	MissingUseKey
+
+	// Return a *MissingTranslationError from Translate
+	MissingError
+)
+
+// MissingTranslationError is returned by Translate when the policy of
+// the messages is MissingError
+type MissingTranslationError struct {
+	Lang string
+	Key  string
+}
+
+func (e *MissingTranslationError) Error() string {
+	return fmt.Sprintf("respond: missing translation %q for language %q", e.Key, e.Lang)
+}
+
+// Messages is a catalog of translations. The translations are compiled
+// into an immutable catalog which AddLanguageTranslation and SetFallback
+// replace as a whole, so lookups take no lock. The translations and the
+// fallback chains are only changed through these methods
 type Messages struct {
-	Lang      string
-	Success   string
-	Failed    string
-	Errors    map[string]map[string]interface{}
-	Languages map[string]map[string]interface{}
+	Lang    string
+	Success string
+	Failed  string
+
+	// Errors holds every error of the fallback chain of Lang, it is
+	// shared with the compiled catalog and must not be changed
+	Errors map[string]map[string]interface{}
+
+	languages map[string]map[string]interface{}
+
+	// fallbacks holds the languages to try, in order, when a key is
+	// missing from a language. fa-IR falls back to fa when it has no
+	// chain configured
+	fallbacks map[string][]string
+
+	// MissingPolicy defines the message used when a key is missing from
+	// the whole fallback chain
+	MissingPolicy MissingPolicy
+
+	// OnMissing is called every time a key is missing from the whole
+	// fallback chain
+	OnMissing func(lang, key string)
+
 	sync.RWMutex
+
+	// The compiled *catalog of the languages and the fallbacks
+	compiled atomic.Value
 }
 
 func NewMessages() *Messages {
 	return &Messages{
-		Lang: "en",
-		Languages: map[string]map[string]interface{}{
+		Lang: DefaultLanguage,
+		languages: map[string]map[string]interface{}{
 			"fa": fa.Messages,
 			"en": en.Messages,
 		},
+		fallbacks: map[string][]string{},
+	}
+}
+
+// The messages of responses which are created without a catalog, their
+// language is the DefaultLanguage of the package
+var defaultMessages = &Messages{
+	languages: map[string]map[string]interface{}{
+		"fa": fa.Messages,
+		"en": en.Messages,
+	},
+	fallbacks: map[string][]string{},
+}
+
+// Copy the messages, so a response can change its own messages. The
+// copy shares the translations and the compiled catalog, which are only
+// copied when one of them is changed
+func (m *Messages) clone() *Messages {
+	// compile the catalog once, so it is shared by every copy
+	compiled := m.catalog()
+	m.RLock()
+	defer m.RUnlock()
+	c := &Messages{
+		Lang:          m.Lang,
+		languages:     m.languages,
+		fallbacks:     m.fallbacks,
+		MissingPolicy: m.MissingPolicy,
+		OnMissing:     m.OnMissing,
 	}
+	c.compiled.Store(compiled)
+	return c
+}
+
+// Get the translations of the languages, the catalogs must not be
+// changed, AddLanguageTranslation replaces the catalog of a language
+//
+// @return map[string]map[string]interface{}
+func (m *Messages) Languages() map[string]map[string]interface{} {
+	m.RLock()
+	defer m.RUnlock()
+	languages := make(map[string]map[string]interface{}, len(m.languages))
+	for lang, messages := range m.languages {
+		languages[lang] = messages
+	}
+	return languages
+}
+
+// Get the fallback chains which are set with SetFallback
+//
+// @return map[string][]string
+func (m *Messages) Fallbacks() map[string][]string {
+	m.RLock()
+	defer m.RUnlock()
+	fallbacks := make(map[string][]string, len(m.fallbacks))
+	for lang, chain := range m.fallbacks {
+		fallbacks[lang] = append([]string(nil), chain...)
+	}
+	return fallbacks
 }
 
 func (m *Messages) AddLanguageTranslation(lang string, messages map[string]interface{}) {
 	m.Lock()
-	m.Languages[lang] = messages
-	m.Unlock()
+	defer m.Unlock()
+	languages := make(map[string]map[string]interface{}, len(m.languages)+1)
+	for l, catalog := range m.languages {
+		languages[l] = catalog
+	}
+	languages[lang] = messages
+	m.languages = languages
+	m.compiled.Store(compileCatalog(m.languages, m.fallbacks, m.previous(), lang))
+}
+
+// Set the fallback chain of a language
+//
+//      messages.SetFallback("fa-IR", "fa", "en")
+//
+// @param lang string, fallbacks ...string
+func (m *Messages) SetFallback(lang string, fallbacks ...string) {
+	m.Lock()
+	defer m.Unlock()
+	chains := make(map[string][]string, len(m.fallbacks)+1)
+	for l, chain := range m.fallbacks {
+		chains[l] = chain
+	}
+	chains[lang] = append([]string(nil), fallbacks...)
+	m.fallbacks = chains
+	m.compiled.Store(compileCatalog(m.languages, m.fallbacks, m.previous(), ""))
+}
+
+// Get the list of languages which have a translation
+//
+// @return []string
+func (m *Messages) SupportedLanguages() []string {
+	return append([]string(nil), m.catalog().languages...)
+}
+
+// Translate a dotted message key like "errors.5404.message" in the
+// current language, walking the fallback chain of the language
+//
+// @param key string
+// @return (string, error)
+func (m *Messages) Translate(key string) (string, error) {
+	return m.TranslateArgs(key, nil)
+}
+
+// Translate a dotted message key and replace its {name} placeholders
+// with the arguments, the count argument picks the plural form of the
+// message
+//
+//      messages.TranslateArgs("errors.1001.message", respond.Args{"field": "email"})
+//
+// @param key string, args Args
+// @return (string, error)
+func (m *Messages) TranslateArgs(key string, args Args) (string, error) {
+	return m.translate(m.language(), key, args, nil)
+}
+
+// Translate a key in a language, onMissing is called with OnMissing for
+// the keys which are missing from the whole fallback chain
+func (m *Messages) translate(lang, key string, args Args, onMissing func(lang, key string)) (string, error) {
+	c := m.catalog()
+	if message, l, ok := c.find(lang, key, args); ok {
+		return interpolate(message, l, args), nil
+	}
+	if m.OnMissing != nil {
+		m.OnMissing(lang, key)
+	}
+	if onMissing != nil {
+		onMissing(lang, key)
+	}
+	switch m.MissingPolicy {
+	case MissingError:
+		return "", &MissingTranslationError{Lang: lang, Key: key}
+	case MissingUseDefault:
+		if message, l, ok := c.find(DefaultLanguage, key, args); ok {
+			return interpolate(message, l, args), nil
+		}
+	}
+	return key, nil
+}
+
+// Get the language of the messages, the DefaultLanguage when it is not
+// set
+func (m *Messages) language() string {
+	if m.Lang == "" {
+		return DefaultLanguage
+	}
+	return m.Lang
+}
+
+// Get the compiled catalog of the messages, it is compiled on first use
+func (m *Messages) catalog() *catalog {
+	if c, ok := m.compiled.Load().(*catalog); ok {
+		return c
+	}
+	m.Lock()
+	defer m.Unlock()
+	// the catalog may be compiled or changed while waiting for the lock
+	if c, ok := m.compiled.Load().(*catalog); ok {
+		return c
+	}
+	c := compileCatalog(m.languages, m.fallbacks, nil, "")
+	m.compiled.Store(c)
+	return c
+}
+
+// Get the current compiled catalog, nil when it is not compiled yet
+func (m *Messages) previous() *catalog {
+	c, _ := m.compiled.Load().(*catalog)
+	return c
+}
+
+// catalog is the compiled form of the translations, it is never changed
+// once it is built
+type catalog struct {
+	// Translations the catalog is compiled from, and the flattened keys
+	// of every language to their messages or plural forms
+	sources  map[string]map[string]interface{}
+	messages map[string]map[string]interface{}
+
+	// Fallback chains of the languages
+	fallbacks map[string][]string
+
+	// Sorted languages and their lower case tags for negotiation
+	languages []string
+	supported map[string]string
+
+	// Resolved fallback chains of the languages of the catalog
+	views map[string]*catalogView
+
+	// Pre-encoded responses of fixed messages, see writeStatic
+	static sync.Map
+}
+
+// What a language resolves to through its fallback chain
+type catalogView struct {
+	chain []string
+
+	// Success and failed status texts, empty when they are missing
+	success string
+	failed  string
+
+	// Every error of the fallback chain, the entries of the language
+	// itself take precedence over its fallbacks
+	errors map[string]map[string]interface{}
+}
+
+// Compile translations, the flattened messages of a previous catalog are
+// reused for every language but the changed one
+func compileCatalog(languages map[string]map[string]interface{}, fallbacks map[string][]string, previous *catalog, changed string) *catalog {
+	c := &catalog{
+		sources:   languages,
+		messages:  make(map[string]map[string]interface{}, len(languages)),
+		fallbacks: make(map[string][]string, len(fallbacks)),
+		languages: make([]string, 0, len(languages)),
+		supported: make(map[string]string, len(languages)),
+		views:     make(map[string]*catalogView, len(languages)+len(fallbacks)),
+	}
+	for lang, chain := range fallbacks {
+		c.fallbacks[lang] = append([]string(nil), chain...)
+	}
+	for lang, messages := range languages {
+		if flat, ok := previous.flattened(lang); ok && lang != changed {
+			c.messages[lang] = flat
+		} else {
+			c.messages[lang] = flattenMessages(messages)
+		}
+		c.languages = append(c.languages, lang)
+		c.supported[strings.ToLower(lang)] = lang
+	}
+	sort.Strings(c.languages)
+
+	for _, lang := range c.languages {
+		c.views[lang] = c.resolve(lang)
+	}
+	for lang := range fallbacks {
+		if _, ok := c.views[lang]; !ok {
+			c.views[lang] = c.resolve(lang)
+		}
+	}
+	return c
+}
+
+// Get the flattened messages of a language of the catalog
+func (c *catalog) flattened(lang string) (map[string]interface{}, bool) {
+	if c == nil {
+		return nil, false
+	}
+	flat, ok := c.messages[lang]
+	return flat, ok
+}
+
+// Flatten the nested levels of a language into dotted keys, plural forms
+// are kept as a single message
+func flattenMessages(messages map[string]interface{}) map[string]interface{} {
+	flat := map[string]interface{}{}
+	flattenCatalog("", messages, flat)
+	delete(flat, "")
+	for key, value := range flat {
+		if level := levelOf(value); level != nil {
+			flat[key] = level
+		}
+	}
+	return flat
+}
+
+// Resolve the fallback chain of a language
+func (c *catalog) resolve(lang string) *catalogView {
+	view := &catalogView{chain: c.chain(lang)}
+	if message, _, ok := c.findIn(view.chain, "success", nil); ok {
+		view.success = message
+	}
+	if message, _, ok := c.findIn(view.chain, "failed", nil); ok {
+		view.failed = message
+	}
+	errors := map[string]map[string]interface{}{}
+	for i := len(view.chain) - 1; i >= 0; i-- {
+		for code, entry := range levelOf(lookupPath(c.sources[view.chain[i]], []string{"errors"})) {
+			fields := levelOf(entry)
+			if fields == nil {
+				continue
+			}
+			merged, ok := errors[code]
+			if !ok {
+				merged = make(map[string]interface{}, len(fields))
+				errors[code] = merged
+			}
+			for k, v := range fields {
+				merged[k] = v
+			}
+		}
+	}
+	view.errors = errors
+	return view
+}
+
+// Get the resolved fallback chain of a language, languages outside of
+// the catalog are resolved every time
+func (c *catalog) view(lang string) *catalogView {
+	if view, ok := c.views[lang]; ok {
+		return view
+	}
+	return c.resolve(lang)
+}
+
+// Find a message in the fallback chain of a language, the language the
+// message is found in is returned with it
+func (c *catalog) find(lang, key string, args Args) (string, string, bool) {
+	if view, ok := c.views[lang]; ok {
+		return c.findIn(view.chain, key, args)
+	}
+	return c.findIn(c.chain(lang), key, args)
+}
+
+func (c *catalog) findIn(chain []string, key string, args Args) (string, string, bool) {
+	for _, l := range chain {
+		if message, ok := resolveMessage(c.messages[l][key], l, args); ok {
+			return message, l, true
+		}
+	}
+	return "", "", false
+}
+
+// Get the languages to look a key up in, the language itself first
+func (c *catalog) chain(lang string) []string {
+	chain := []string{lang}
+	if fallbacks, ok := c.fallbacks[lang]; ok {
+		chain = append(chain, fallbacks...)
+	} else {
+		for parent := parentTag(lang); parent != ""; parent = parentTag(parent) {
+			chain = append(chain, parent)
+		}
+	}
+	return chain
+}
+
+// Walk nested translation maps, both map[string]interface{} and
+// map[string]map[string]interface{} are accepted as a level
+func lookupPath(node interface{}, path []string) interface{} {
+	for _, key := range path {
+		switch n := node.(type) {
+		case map[string]interface{}:
+			node = n[key]
+		case map[string]map[string]interface{}:
+			v, ok := n[key]
+			if !ok {
+				return nil
+			}
+			node = v
+		case map[string]string:
+			v, ok := n[key]
+			if !ok {
+				return nil
+			}
+			node = v
+		default:
+			return nil
+		}
+	}
+	return node
+}
+
+// Get the keys and values of a translation level as map[string]interface{}
+func levelOf(node interface{}) map[string]interface{} {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		return n
+	case map[string]map[string]interface{}:
+		level := make(map[string]interface{}, len(n))
+		for k, v := range n {
+			level[k] = v
+		}
+		return level
+	case map[string]string:
+		level := make(map[string]interface{}, len(n))
+		for k, v := range n {
+			level[k] = v
+		}
+		return level
+	}
+	return nil
 }
 
 // Load config of response language
 //
+// Errors holds every error of the fallback chain, the entries of the
+// language itself take precedence over its fallbacks. It is shared by
+// the responses and must not be changed
+//
 // @author Alireza Josheghani <josheghani.dev@gmail.com>
 // @since 15 Mar 2018
 // @return *Message
 func (m *Messages) load() {
-	m.RLock()
-	translation := m.Languages[m.Lang]
-	m.Errors = translation["errors"].(map[string]map[string]interface{})
-	m.Success = translation["success"].(string)
-	m.Failed = translation["failed"].(string)
-	m.RUnlock()
+	lang := m.language()
+	m.Success = m.statusText(lang, "success", nil)
+	m.Failed = m.statusText(lang, "failed", nil)
+	m.Errors = m.catalog().view(lang).errors
+}
+
+// Get the success or the failed status text of a language
+func (m *Messages) statusText(lang, key string, onMissing func(lang, key string)) string {
+	var text string
+	if view, ok := m.catalog().views[lang]; ok {
+		text = view.success
+		if key == "failed" {
+			text = view.failed
+		}
+	}
+	if text == "" {
+		text, _ = m.translate(lang, key, nil, onMissing)
+	}
+	return text
 }
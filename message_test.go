@@ -0,0 +1,272 @@
+package respond
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnknownLanguageFallsBackToDefault(t *testing.T) {
+
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+	assert.NotPanics(t, func() {
+		NewWithWriter(recorder).Language("de").NotFound()
+	})
+
+	assert.Equal(t, http.StatusNotFound, recorder.Result().StatusCode)
+
+	expected, err := getExpectedMap(recorder.Body)
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"message": "Oops... The requested page not found!",
+		"status":  "failed",
+		"error":   float64(5404),
+	}, expected)
+}
+
+func TestRegionalLanguageFallsBackToBase(t *testing.T) {
+
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+	NewWithWriter(recorder).Language("fa-IR").NotFound()
+
+	expected, err := getExpectedMap(recorder.Body)
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"status":  "نا موفق",
+		"error":   float64(5404),
+		"message": ".صفحه درخواست شده پیدا نمیشود",
+	}, expected)
+}
+
+func TestFallbackChainPerKey(t *testing.T) {
+
+	t.Parallel()
+
+	var (
+		recorder = httptest.NewRecorder()
+		respond  = NewWithWriter(recorder)
+	)
+
+	respond.Messages().AddLanguageTranslation("fa-AF", map[string]interface{}{
+		"failed": "ناکام",
+		"errors": map[string]interface{}{
+			"5405": map[string]interface{}{
+				"message": "متد مجاز نیست",
+			},
+		},
+	})
+	respond.Messages().SetFallback("fa-AF", "fa", "en")
+
+	respond.Language("fa-AF").NotFound()
+
+	expected, err := getExpectedMap(recorder.Body)
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"status":  "ناکام",
+		"error":   float64(5404),
+		"message": ".صفحه درخواست شده پیدا نمیشود",
+	}, expected)
+}
+
+func TestMissingTranslationPolicies(t *testing.T) {
+
+	t.Parallel()
+
+	var missing []string
+	messages := NewMessages()
+	messages.AddLanguageTranslation("ru", map[string]interface{}{
+		"success": "успех",
+	})
+	messages.Lang = "ru"
+	messages.OnMissing = func(lang, key string) {
+		missing = append(missing, lang+":"+key)
+	}
+
+	message, err := messages.Translate("errors.5404.message")
+	assert.NoError(t, err)
+	assert.Equal(t, "Oops... The requested page not found!", message)
+
+	messages.MissingPolicy = MissingUseKey
+	message, err = messages.Translate("errors.5404.message")
+	assert.NoError(t, err)
+	assert.Equal(t, "errors.5404.message", message)
+
+	messages.MissingPolicy = MissingError
+	_, err = messages.Translate("errors.5404.message")
+	assert.Equal(t, &MissingTranslationError{Lang: "ru", Key: "errors.5404.message"}, err)
+
+	assert.Equal(t, []string{
+		"ru:errors.5404.message",
+		"ru:errors.5404.message",
+		"ru:errors.5404.message",
+	}, missing)
+}
+
+func TestUnknownErrorCode(t *testing.T) {
+
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+	NewWithWriter(recorder).Error(400, 9999)
+
+	expected, err := getExpectedMap(recorder.Body)
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"status":  "failed",
+		"error":   float64(9999),
+		"message": "errors.9999.message",
+	}, expected)
+}
+
+func TestMessagesCopyOnWrite(t *testing.T) {
+
+	t.Parallel()
+
+	messages := NewMessages()
+	languages := messages.Languages()
+	messages.Lang = "de"
+	message, err := messages.Translate("success")
+	assert.NoError(t, err)
+	assert.Equal(t, "success", message)
+
+	messages.AddLanguageTranslation("de", map[string]interface{}{"success": "erfolg"})
+	message, _ = messages.Translate("success")
+	assert.Equal(t, "erfolg", message)
+
+	// the catalog is replaced, the previous one and the built-in
+	// translations are not changed
+	assert.NotContains(t, languages, "de")
+	assert.Equal(t, []string{"de", "en", "fa"}, messages.SupportedLanguages())
+	assert.Equal(t, []string{"en", "fa"}, NewMessages().SupportedLanguages())
+
+	messages.SetFallback("de", "fa")
+	message, _ = messages.Translate("failed")
+	assert.Equal(t, "نا موفق", message)
+
+	// the messages of a response are its own
+	r := NewWithWriter(httptest.NewRecorder())
+	r.Messages().AddLanguageTranslation("de", map[string]interface{}{"failed": "fehlgeschlagen"})
+	assert.Equal(t, "fehlgeschlagen", r.Language("de").Messages().Failed)
+	assert.Equal(t, []string{"en", "fa"}, NewWithWriter(httptest.NewRecorder()).Messages().SupportedLanguages())
+}
+
+func TestResponseMessagesShareTheCatalog(t *testing.T) {
+
+	t.Parallel()
+
+	// the errors of the responses are the ones of the compiled catalog
+	errors := NewWithWriter(httptest.NewRecorder()).Messages().Errors
+	assert.Equal(t, reflect.ValueOf(errors).Pointer(), reflect.ValueOf(NewWithWriter(httptest.NewRecorder()).Messages().Errors).Pointer())
+
+	// and they are only copied when the messages of a response change
+	r := NewWithWriter(httptest.NewRecorder())
+	r.Messages().AddLanguageTranslation("en", map[string]interface{}{
+		"errors": map[string]interface{}{
+			"5404": map[string]interface{}{"message": "changed"},
+		},
+	})
+	assert.Equal(t, "changed", r.Messages().Errors["5404"]["message"])
+	assert.Equal(t, "Oops... The requested page not found!", errors["5404"]["message"])
+}
+
+func TestMessagesReadOnly(t *testing.T) {
+
+	t.Parallel()
+
+	messages := NewMessages()
+	messages.SetFallback("fa-AF", "fa")
+
+	// the languages and the fallbacks are copies
+	languages := messages.Languages()
+	languages["de"] = map[string]interface{}{"success": "erfolg"}
+	fallbacks := messages.Fallbacks()
+	fallbacks["fa-AF"][0] = "en"
+
+	assert.Equal(t, []string{"en", "fa"}, messages.SupportedLanguages())
+	assert.Equal(t, map[string][]string{"fa-AF": {"fa"}}, messages.Fallbacks())
+}
+
+func TestMessagesConcurrentUpdates(t *testing.T) {
+
+	t.Parallel()
+
+	messages := NewMessages()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 50; i++ {
+			messages.AddLanguageTranslation("de", map[string]interface{}{"success": "erfolg"})
+			messages.SetFallback("de", "en")
+		}
+	}()
+	for i := 0; i < 200; i++ {
+		message, err := messages.TranslateArgs("errors.1001.message", Args{"field": "email"})
+		assert.NoError(t, err)
+		assert.Equal(t, "Oops... Requested field email is not found!", message)
+	}
+	<-done
+}
+
+func BenchmarkTranslate(b *testing.B) {
+	messages := NewMessages()
+	messages.Lang = "fa"
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		messages.Translate("errors.5404.message")
+	}
+}
+
+func BenchmarkTranslateParallel(b *testing.B) {
+	messages := NewMessages()
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			messages.TranslateArgs("errors.1001.message", Args{"field": "email"})
+		}
+	})
+}
+
+// The cost of a response of a request, like a handler behind the
+// middleware pays for it
+func BenchmarkRequestNotFound(b *testing.B) {
+	handler := Middleware(Options{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		From(r).NotFound()
+	}))
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("Accept-Language", "fa-IR,fa;q=0.9,en;q=0.8")
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			handler.ServeHTTP(httptest.NewRecorder(), request)
+		}
+	})
+}
+
+func BenchmarkNewWithWriterNotFound(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		NewWithWriter(httptest.NewRecorder()).NotFound()
+	}
+}
+
+// The messages of a response share the catalog, so they cost no copy
+func BenchmarkResponseMessages(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = NewWithWriter(httptest.NewRecorder()).Messages().Errors
+	}
+}
@@ -0,0 +1,125 @@
+package respond
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// Options of the respond middleware
+type Options struct {
+	// DefaultLanguage is used when the Accept-Language header of the
+	// request matches none of the translations, the package
+	// DefaultLanguage is used when it is empty
+	DefaultLanguage string
+
+	// Mode is the output mode of error responses, the DefaultMode is
+	// used when it is not set
+	Mode Mode
+
+	// Messages is the catalog shared by every request, the built-in
+	// catalog is used when it is nil
+	Messages *Messages
+
+	// RequestIDHeader is the header the request ID is read from and
+	// written to, X-Request-ID is used when it is empty
+	RequestIDHeader string
+
+	// RequestID generates the ID of requests which do not have one, a
+	// random 16 bytes hex string is used when it is nil
+	RequestID func() string
+
+	// Challenge is the WWW-Authenticate challenge of the auth errors
+	Challenge Challenge
+
+	// ETag is how the ETag of successful responses is generated
+	ETag ETagMode
+
+	// Envelope is the envelope of the response bodies, DefaultEnvelope
+	// is used when it is nil
+	Envelope *Envelope
+
+	// Statuses is the status profile of the errors, like
+	// StandardStatuses, the statuses are kept when it is nil
+	Statuses StatusProfile
+}
+
+type contextKey struct{}
+
+// Middleware builds a configured Respond for every request and stores it
+// in the context of the request, handlers get it with From
+//
+//      mux := http.NewServeMux()
+//      mux.HandleFunc("/users", func(w http.ResponseWriter, r *http.Request) {
+//        respond.From(r).Succeed(users)
+//      })
+//      http.ListenAndServe(":8080", respond.Middleware(respond.Options{})(mux))
+//
+// @param opts Options
+// @return func(http.Handler) http.Handler
+func Middleware(opts Options) func(http.Handler) http.Handler {
+	return New(opts.options()...).Middleware()
+}
+
+// Get the config options of the middleware options
+func (opts Options) options() []Option {
+	options := []Option{
+		WithMode(opts.Mode),
+		WithChallenge(opts.Challenge),
+		WithETag(opts.ETag),
+		WithStatusProfile(opts.Statuses),
+		WithRequestID(opts.RequestIDHeader, opts.RequestID),
+	}
+	if opts.DefaultLanguage != "" {
+		options = append(options, WithLanguage(opts.DefaultLanguage))
+	}
+	if opts.Messages != nil {
+		options = append(options, WithMessages(opts.Messages))
+	}
+	if opts.Envelope != nil {
+		options = append(options, WithEnvelope(*opts.Envelope))
+	}
+	return options
+}
+
+// Get the Respond of a request which is built by Middleware, nil is
+// returned when the request did not pass through the middleware
+//
+// @param req *http.Request
+// @return *Respond
+func From(req *http.Request) *Respond {
+	return FromContext(req.Context())
+}
+
+// Get the Respond stored in a context
+//
+// @param ctx context.Context
+// @return *Respond
+func FromContext(ctx context.Context) *Respond {
+	r, _ := ctx.Value(contextKey{}).(*Respond)
+	return r
+}
+
+// Store a Respond in a context
+//
+// @param ctx context.Context, r *Respond
+// @return context.Context
+func NewContext(ctx context.Context, r *Respond) context.Context {
+	return context.WithValue(ctx, contextKey{}, r)
+}
+
+// Get the ID of the request of the response
+//
+// @return string
+func (r *Respond) RequestID() string {
+	return r.requestID
+}
+
+func newRequestID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,125 @@
+package respond
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+
+	t.Parallel()
+
+	handler := Middleware(Options{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		From(r).NotFound()
+	}))
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("Accept-Language", "fa-IR")
+	request.Header.Set("Accept", "application/xml, application/json;q=0.5")
+	request.Header.Set("X-Request-ID", "req-1")
+
+	handler.ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Result().StatusCode)
+	assert.Equal(t, MediaTypeXML+"; charset=utf-8", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, "req-1", recorder.Header().Get("X-Request-ID"))
+	assert.Contains(t, recorder.Body.String(), "<status>نا موفق</status>")
+}
+
+func TestMiddlewareOptions(t *testing.T) {
+
+	t.Parallel()
+
+	messages := NewMessages()
+	messages.AddLanguageTranslation("ru", map[string]interface{}{
+		"success": "успех",
+		"failed":  "не смогли",
+	})
+
+	var requestID string
+	handler := Middleware(Options{
+		DefaultLanguage: "ru",
+		Mode:            ModeProblem,
+		Messages:        messages,
+		RequestIDHeader: "X-Trace",
+		RequestID:       func() string { return "generated" },
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID = From(r).RequestID()
+		From(r).Succeed("Test")
+	}))
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("Accept-Language", "de")
+
+	handler.ServeHTTP(recorder, request)
+
+	assert.Equal(t, "generated", requestID)
+	assert.Equal(t, "generated", recorder.Header().Get("X-Trace"))
+
+	expected, err := getExpectedMap(recorder.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, "успех", expected["status"])
+}
+
+func TestMiddlewareDefaultMode(t *testing.T) {
+
+	t.Parallel()
+
+	// options without a mode keep the mode of the config, which is the
+	// DefaultMode for Middleware
+	config := New(WithMode(ModeProblem)).With(Options{}.options()...)
+	handler := config.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		From(r).NotFound()
+	}))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, ProblemContentType+"; charset=utf-8", recorder.Header().Get("Content-Type"))
+
+	recorder = httptest.NewRecorder()
+	assert.NoError(t, config.Writer(recorder).SetMode(ModeEnvelope).SetMode(ModeDefault).NotFound())
+	assert.Equal(t, MediaTypeJSON+"; charset=utf-8", recorder.Header().Get("Content-Type"))
+}
+
+func TestMiddlewareConcurrentRequests(t *testing.T) {
+
+	t.Parallel()
+
+	handler := Middleware(Options{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		From(r).NotFound()
+	}))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		lang := []string{"en", "fa"}[i%2]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			request.Header.Set("Accept-Language", lang)
+			handler.ServeHTTP(recorder, request)
+			expected, err := getExpectedMap(recorder.Body)
+			assert.NoError(t, err)
+			if lang == "fa" {
+				assert.Equal(t, "نا موفق", expected["status"])
+			} else {
+				assert.Equal(t, "failed", expected["status"])
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestFromWithoutMiddleware(t *testing.T) {
+
+	t.Parallel()
+
+	assert.Nil(t, From(httptest.NewRequest(http.MethodGet, "/", nil)))
+}
@@ -0,0 +1,181 @@
+package respond
+
+import (
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Page describes a page of a list for Paginated, offset pagination uses
+// the page number and cursor pagination uses the opaque cursors
+type Page struct {
+	// Cursor pagination is used instead of offset pagination
+	Cursor bool
+
+	// Number of the page starting at 1 for offset pagination
+	Current int
+
+	// Number of items of a page
+	PerPage int
+
+	// Total number of items of offset pagination, it is only known when
+	// HasTotal is set and null otherwise
+	Total    int
+	HasTotal bool
+
+	// Cursors of the next and the previous pages for cursor pagination,
+	// the last page has no next cursor
+	NextCursor string
+	PrevCursor string
+
+	// Names of the query parameters of the links, page, per_page and
+	// cursor are used when they are empty
+	PageParam    string
+	PerPageParam string
+	CursorParam  string
+}
+
+// Page of offset pagination, a negative total is unknown
+//
+// @param current int, perPage int, total int
+// @return Page
+func OffsetPage(current, perPage, total int) Page {
+	return Page{Current: current, PerPage: perPage, Total: total, HasTotal: total >= 0}
+}
+
+// Page of cursor pagination, the total is unknown
+//
+// @param perPage int, next string, prev string
+// @return Page
+func CursorPage(perPage int, next, prev string) Page {
+	return Page{Cursor: true, PerPage: perPage, NextCursor: next, PrevCursor: prev}
+}
+
+// Number of the last page of offset pagination, 0 when the total is
+// unknown
+func (p Page) last() int {
+	if !p.HasTotal || p.Total < 0 || p.PerPage <= 0 {
+		return 0
+	}
+	if p.Total == 0 {
+		return 1
+	}
+	return (p.Total + p.PerPage - 1) / p.PerPage
+}
+
+// The meta.pagination block of the page, pages of both modes have the
+// same keys and the values which are unknown or do not apply are null
+func (p Page) meta() map[string]interface{} {
+	meta := map[string]interface{}{
+		"current":     nil,
+		"per_page":    p.PerPage,
+		"total":       nil,
+		"total_pages": nil,
+		"next_cursor": nil,
+		"prev_cursor": nil,
+	}
+	if p.Cursor {
+		if p.NextCursor != "" {
+			meta["next_cursor"] = p.NextCursor
+		}
+		if p.PrevCursor != "" {
+			meta["prev_cursor"] = p.PrevCursor
+		}
+		return meta
+	}
+	meta["current"] = p.Current
+	if p.HasTotal && p.Total >= 0 {
+		meta["total"] = p.Total
+	}
+	if last := p.last(); last > 0 {
+		meta["total_pages"] = last
+	}
+	return meta
+}
+
+// Respond with a page of a list, the pagination is added as
+// meta.pagination and the first, prev, next and last pages are linked
+// in the Link header (RFC 8288) built from the URL of the request
+//
+//      r.Paginated(users, respond.OffsetPage(2, 20, 120))
+//      // Link: </users?page=1&per_page=20>; rel="first", </users?page=1&per_page=20>; rel="prev", ...
+//
+// @param items interface{}, page Page
+// @return error
+func (r *Respond) Paginated(items interface{}, page Page) error {
+	if page.PageParam == "" {
+		page.PageParam = "page"
+	}
+	if page.PerPageParam == "" {
+		page.PerPageParam = "per_page"
+	}
+	if page.CursorParam == "" {
+		page.CursorParam = "cursor"
+	}
+	if !page.Cursor && page.Current < 1 {
+		page.Current = 1
+	}
+	if links := r.pageLinks(page, itemsLen(items)); links != "" && !r.written {
+		r.writer.Header().Add("Link", links)
+	}
+	r.SetStatusCode(http.StatusOK).SetStatusText(r.successText())
+	data := r.envelopeData()
+	data.Result, data.HasResult = items, true
+	data.Meta = map[string]interface{}{"pagination": page.meta()}
+	return r.write(r.envelope.Build(data), false)
+}
+
+// Build the Link header of a page, nothing is linked without a request
+func (r *Respond) pageLinks(page Page, count int) string {
+	if r.request == nil || r.request.URL == nil {
+		return ""
+	}
+	var links []string
+	link := func(rel string, set func(query url.Values)) {
+		query := r.request.URL.Query()
+		query.Set(page.PerPageParam, strconv.Itoa(page.PerPage))
+		set(query)
+		reference := url.URL{Path: r.request.URL.Path, RawPath: r.request.URL.RawPath, RawQuery: query.Encode()}
+		links = append(links, "<"+reference.String()+`>; rel="`+rel+`"`)
+	}
+
+	if page.Cursor {
+		link("first", func(query url.Values) { query.Del(page.CursorParam) })
+		if page.PrevCursor != "" {
+			link("prev", func(query url.Values) { query.Set(page.CursorParam, page.PrevCursor) })
+		}
+		if page.NextCursor != "" {
+			link("next", func(query url.Values) { query.Set(page.CursorParam, page.NextCursor) })
+		}
+		return strings.Join(links, ", ")
+	}
+
+	number := func(n int) func(query url.Values) {
+		return func(query url.Values) { query.Set(page.PageParam, strconv.Itoa(n)) }
+	}
+	last := page.last()
+	link("first", number(1))
+	if page.Current > 1 {
+		link("prev", number(page.Current-1))
+	}
+	// without a total there is a next page while the pages are full
+	if last > 0 && page.Current < last || last == 0 && count >= page.PerPage && page.PerPage > 0 {
+		link("next", number(page.Current+1))
+	}
+	if last > 0 {
+		link("last", number(last))
+	}
+	return strings.Join(links, ", ")
+}
+
+// Number of the items of a slice or an array, -1 for other values
+func itemsLen(items interface{}) int {
+	v := reflect.ValueOf(items)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		return v.Len()
+	}
+	return -1
+}
@@ -0,0 +1,136 @@
+package respond
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaginatedOffset(t *testing.T) {
+
+	t.Parallel()
+
+	var (
+		recorder = httptest.NewRecorder()
+		req      = httptest.NewRequest(http.MethodGet, "/users?page=2&per_page=20&sort=name", nil)
+	)
+	assert.NoError(t, NewWithRequest(recorder, req).Paginated([]string{"a", "b"}, OffsetPage(2, 20, 45)))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, `</users?page=1&per_page=20&sort=name>; rel="first", `+
+		`</users?page=1&per_page=20&sort=name>; rel="prev", `+
+		`</users?page=3&per_page=20&sort=name>; rel="next", `+
+		`</users?page=3&per_page=20&sort=name>; rel="last"`, recorder.Header().Get("Link"))
+	assert.JSONEq(t, `{
+		"status": "success",
+		"result": ["a", "b"],
+		"meta": {"pagination": {"total": 45, "per_page": 20, "current": 2, "total_pages": 3, "next_cursor": null, "prev_cursor": null}}
+	}`, recorder.Body.String())
+}
+
+func TestPaginatedLastPage(t *testing.T) {
+
+	t.Parallel()
+
+	var (
+		recorder = httptest.NewRecorder()
+		req      = httptest.NewRequest(http.MethodGet, "/users", nil)
+	)
+	assert.NoError(t, NewWithRequest(recorder, req).Paginated([]string{}, Page{Current: 1, PerPage: 10, Total: 0, HasTotal: true, PageParam: "p"}))
+
+	assert.Equal(t, `</users?p=1&per_page=10>; rel="first", </users?p=1&per_page=10>; rel="last"`, recorder.Header().Get("Link"))
+	assert.JSONEq(t, `{
+		"status": "success",
+		"result": [],
+		"meta": {"pagination": {"total": 0, "per_page": 10, "current": 1, "total_pages": 1, "next_cursor": null, "prev_cursor": null}}
+	}`, recorder.Body.String())
+}
+
+func TestPaginatedWithoutTotal(t *testing.T) {
+
+	t.Parallel()
+
+	var (
+		recorder = httptest.NewRecorder()
+		req      = httptest.NewRequest(http.MethodGet, "/users?page=1", nil)
+	)
+	assert.NoError(t, NewWithRequest(recorder, req).Paginated([]int{1, 2}, OffsetPage(1, 2, -1)))
+
+	assert.Equal(t, `</users?page=1&per_page=2>; rel="first", </users?page=2&per_page=2>; rel="next"`, recorder.Header().Get("Link"))
+	assert.JSONEq(t, `{
+		"status": "success",
+		"result": [1, 2],
+		"meta": {"pagination": {"per_page": 2, "current": 1, "total": null, "total_pages": null, "next_cursor": null, "prev_cursor": null}}
+	}`, recorder.Body.String())
+}
+
+func TestPaginatedCursor(t *testing.T) {
+
+	t.Parallel()
+
+	var (
+		recorder = httptest.NewRecorder()
+		req      = httptest.NewRequest(http.MethodGet, "/events?cursor=b2Zmc2V0OjIw&per_page=20", nil)
+	)
+	assert.NoError(t, NewWithRequest(recorder, req).Paginated([]int{1}, CursorPage(20, "b2Zmc2V0OjQw", "b2Zmc2V0OjA=")))
+
+	assert.Equal(t, `</events?per_page=20>; rel="first", `+
+		`</events?cursor=b2Zmc2V0OjA%3D&per_page=20>; rel="prev", `+
+		`</events?cursor=b2Zmc2V0OjQw&per_page=20>; rel="next"`, recorder.Header().Get("Link"))
+	assert.JSONEq(t, `{
+		"status": "success",
+		"result": [1],
+		"meta": {"pagination": {"total": null, "per_page": 20, "current": null, "total_pages": null, "next_cursor": "b2Zmc2V0OjQw", "prev_cursor": "b2Zmc2V0OjA="}}
+	}`, recorder.Body.String())
+}
+
+func TestPaginatedWithoutRequest(t *testing.T) {
+
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+	assert.NoError(t, NewWithWriter(recorder).Paginated([]int{}, CursorPage(20, "", "")))
+
+	assert.Empty(t, recorder.Header().Get("Link"))
+	assert.JSONEq(t, `{
+		"status": "success",
+		"result": [],
+		"meta": {"pagination": {"total": null, "per_page": 20, "current": null, "total_pages": null, "next_cursor": null, "prev_cursor": null}}
+	}`, recorder.Body.String())
+}
+
+func TestPaginatedMode(t *testing.T) {
+
+	t.Parallel()
+
+	// a page without a number is the first page of offset pagination
+	recorder := httptest.NewRecorder()
+	assert.NoError(t, NewWithWriter(recorder).Paginated([]int{}, Page{PerPage: 10, Total: 5, HasTotal: true}))
+	assert.JSONEq(t, `{
+		"status": "success",
+		"result": [],
+		"meta": {"pagination": {"total": 5, "per_page": 10, "current": 1, "total_pages": 1, "next_cursor": null, "prev_cursor": null}}
+	}`, recorder.Body.String())
+
+	// the total of a page is unknown unless it is set
+	recorder = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+	assert.NoError(t, NewWithRequest(recorder, req).Paginated([]int{1, 2}, Page{Current: 1, PerPage: 2}))
+	assert.Equal(t, `</users?page=1&per_page=2>; rel="first", </users?page=2&per_page=2>; rel="next"`, recorder.Header().Get("Link"))
+	assert.JSONEq(t, `{
+		"status": "success",
+		"result": [1, 2],
+		"meta": {"pagination": {"total": null, "per_page": 2, "current": 1, "total_pages": null, "next_cursor": null, "prev_cursor": null}}
+	}`, recorder.Body.String())
+
+	// cursor pages never report a total
+	recorder = httptest.NewRecorder()
+	assert.NoError(t, NewWithWriter(recorder).Paginated([]int{}, Page{Cursor: true, PerPage: 10, NextCursor: "b2Zmc2V0OjEw"}))
+	assert.JSONEq(t, `{
+		"status": "success",
+		"result": [],
+		"meta": {"pagination": {"total": null, "per_page": 10, "current": null, "total_pages": null, "next_cursor": "b2Zmc2V0OjEw", "prev_cursor": null}}
+	}`, recorder.Body.String())
+}
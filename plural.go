@@ -0,0 +1,107 @@
+package respond
+
+import (
+	"math"
+	"strings"
+	"sync"
+)
+
+// CLDR plural categories
+const (
+	PluralZero  = "zero"
+	PluralOne   = "one"
+	PluralTwo   = "two"
+	PluralFew   = "few"
+	PluralMany  = "many"
+	PluralOther = "other"
+)
+
+// PluralRule picks the CLDR plural category of a number
+type PluralRule func(n float64) string
+
+var pluralRules = struct {
+	rules map[string]PluralRule
+	sync.RWMutex
+}{rules: map[string]PluralRule{
+	// one: i = 1 and v = 0
+	"en": func(n float64) string {
+		if n == 1 {
+			return PluralOne
+		}
+		return PluralOther
+	},
+	// one: i = 0 or n = 1
+	"fa": func(n float64) string {
+		if math.Abs(n) < 1 || n == 1 {
+			return PluralOne
+		}
+		return PluralOther
+	},
+}}
+
+// Register the plural rule of a language, regional tags like fa-IR use
+// the rule of their base language when they have no rule of their own
+//
+//      respond.RegisterPluralRule("de", func(n float64) string {
+//        if n == 1 {
+//          return respond.PluralOne
+//        }
+//        return respond.PluralOther
+//      })
+//
+// @param lang string, rule PluralRule
+func RegisterPluralRule(lang string, rule PluralRule) {
+	pluralRules.Lock()
+	pluralRules.rules[strings.ToLower(lang)] = rule
+	pluralRules.Unlock()
+}
+
+// Get the plural category of a number in a language, every number is
+// other in languages without a rule
+func pluralCategory(lang string, n float64) string {
+	pluralRules.RLock()
+	defer pluralRules.RUnlock()
+	for tag := strings.ToLower(lang); tag != ""; tag = parentTag(tag) {
+		if rule, ok := pluralRules.rules[tag]; ok {
+			return rule(n)
+		}
+	}
+	return PluralOther
+}
+
+// Get the plural categories which the rule of a language picks for whole
+// numbers, in the order of zero, one, two, few, many and other. It is
+// also the order of the plural forms of gettext
+//
+//      respond.PluralCategories("en") // [one other]
+//
+// @param lang string
+// @return []string
+func PluralCategories(lang string) []string {
+	picked := map[string]bool{}
+	for n := 0; n <= 1000; n++ {
+		picked[pluralCategory(lang, float64(n))] = true
+	}
+	var categories []string
+	for _, category := range []string{PluralZero, PluralOne, PluralTwo, PluralFew, PluralMany, PluralOther} {
+		if picked[category] {
+			categories = append(categories, category)
+		}
+	}
+	return categories
+}
+
+// Whether a catalog entry holds the plural forms of a message
+func isPluralForms(forms map[string]interface{}) bool {
+	if _, ok := forms[PluralOther]; !ok {
+		return false
+	}
+	for category := range forms {
+		switch category {
+		case PluralZero, PluralOne, PluralTwo, PluralFew, PluralMany, PluralOther:
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,101 @@
+package respond
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// Output mode of error responses
+type Mode int
+
+const (
+	// The DefaultMode, options with it keep the mode which is set
+	ModeDefault Mode = iota
+
+	// The {status, message, error} envelope
+	ModeEnvelope
+
+	// RFC 9457 application/problem+json documents
+	ModeProblem
+)
+
+// ProblemContentType is the media type of problem documents
+const ProblemContentType = "application/problem+json"
+
+// ProblemXMLContentType is the media type of problem documents in XML
+const ProblemXMLContentType = "application/problem+xml"
+
+var (
+	// DefaultMode is the output mode of new Respond instances
+	DefaultMode = ModeEnvelope
+
+	// ProblemTypeURI is the prefix of the type member of problem
+	// documents, the error code is appended to it. Problems are typed
+	// as about:blank when it is empty
+	ProblemTypeURI = ""
+)
+
+// Set the output mode of error responses, ModeDefault sets the
+// DefaultMode
+//
+// @param mode Mode
+// @return *Respond
+func (r *Respond) SetMode(mode Mode) *Respond {
+	if mode == ModeDefault {
+		mode = DefaultMode
+	}
+	r.mode = mode
+	return r
+}
+
+// Pass response as a problem document
+//
+//      problem := map[string]interface{} {
+//        "type":   "about:blank",
+//        "title":  "Not Found",
+//        "status": 404,
+//        "detail": "Oops... The requested page not found!",
+//        "code":   5404,
+//      }
+//
+// @param problem map[string]interface{}
+// @return error
+func (r *Respond) RespondWithProblem(problem map[string]interface{}) error {
+	return r.write(problem, true)
+}
+
+// Build the problem document of a catalog error, the error code and the
+// cat and short fields of the catalog are added as extension members
+func (r *Respond) problem(statusCode, errorCode int, detail string) map[string]interface{} {
+	code := strconv.Itoa(errorCode)
+	title := http.StatusText(statusCode)
+	if title == "" {
+		title = r.failedText()
+	}
+	problemType := "about:blank"
+	if ProblemTypeURI != "" {
+		problemType = ProblemTypeURI + code
+	}
+	problem := map[string]interface{}{
+		"type":   problemType,
+		"title":  title,
+		"status": statusCode,
+		"detail": detail,
+		"code":   errorCode,
+	}
+	if r.request != nil {
+		problem["instance"] = r.request.URL.RequestURI()
+	}
+	entry := r.errorEntry(code)
+	for _, member := range []string{"cat", "short"} {
+		if v, ok := entry[member]; ok {
+			problem[member] = v
+		}
+	}
+	return problem
+}
+
+// Get the catalog entry of an error code in the language of the response
+func (r *Respond) errorEntry(code string) map[string]interface{} {
+	return r.catalog().catalog().view(r.language()).errors[code]
+}
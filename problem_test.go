@@ -0,0 +1,85 @@
+package respond
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProblemNotFound(t *testing.T) {
+
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/users/12?full=1", nil)
+	NewWithRequest(recorder, request).SetMode(ModeProblem).NotFound()
+
+	assert.Equal(t, http.StatusNotFound, recorder.Result().StatusCode)
+	assert.Equal(t, ProblemContentType+"; charset=utf-8", recorder.Header().Get("Content-Type"))
+
+	expected, err := getExpectedMap(recorder.Body)
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"type":     "about:blank",
+		"title":    "Not Found",
+		"status":   float64(404),
+		"detail":   "Oops... The requested page not found!",
+		"instance": "/users/12?full=1",
+		"code":     float64(5404),
+		"short":    "not-found",
+	}, expected)
+}
+
+func TestProblemCatalogMembers(t *testing.T) {
+
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+	NewWithWriter(recorder).Language("fa").SetMode(ModeProblem).Error(401, 3001)
+
+	assert.Equal(t, http.StatusUnauthorized, recorder.Result().StatusCode)
+
+	expected, err := getExpectedMap(recorder.Body)
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"type":   "about:blank",
+		"title":  "Unauthorized",
+		"status": float64(401),
+		"detail": ".شما به سیستم وارد نشده اید",
+		"code":   float64(3001),
+		"cat":    "auth",
+		"short":  "not-logged-on",
+	}, expected)
+}
+
+func TestProblemValidationErrors(t *testing.T) {
+
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+	NewWithWriter(recorder).SetMode(ModeProblem).ValidationErrors(map[string]interface{}{
+		"name": "required",
+	})
+
+	assert.Equal(t, 420, recorder.Result().StatusCode)
+	assert.Equal(t, ProblemContentType+"; charset=utf-8", recorder.Header().Get("Content-Type"))
+
+	expected, err := getExpectedMap(recorder.Body)
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"type":   "about:blank",
+		"title":  "failed",
+		"status": float64(420),
+		"detail": "Validation Error",
+		"code":   float64(5420),
+		"short":  "validation-failed",
+		"errors": map[string]interface{}{
+			"name": "required",
+		},
+	}, expected)
+}
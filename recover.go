@@ -0,0 +1,119 @@
+package respond
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"runtime/debug"
+)
+
+// Logger is the logger of the recovery middleware, *log.Logger
+// satisfies it
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// Options of the recovery middleware
+type RecoverOptions struct {
+	// Logger logs the recovered panics with their stack traces, the
+	// standard logger is used when it is nil
+	Logger Logger
+
+	// Debug adds the panic value and the stack trace to the body of
+	// responses, it is meant for local development only
+	Debug bool
+}
+
+// Recover catches the panics of handlers, logs them and responds with a
+// localised 5500 internal server error in the negotiated format when
+// nothing is written yet. The Respond of Middleware is used when the
+// request passed through it
+//
+//      handler := respond.Middleware(respond.Options{})(
+//        respond.Recover(respond.RecoverOptions{})(mux),
+//      )
+//
+// @param opts RecoverOptions
+// @return func(http.Handler) http.Handler
+func Recover(opts RecoverOptions) func(http.Handler) http.Handler {
+	if opts.Logger == nil {
+		opts.Logger = log.Default()
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			tw := &trackingWriter{ResponseWriter: w}
+			defer func() {
+				v := recover()
+				if v == nil {
+					return
+				}
+				if v == http.ErrAbortHandler {
+					panic(v)
+				}
+				stack := debug.Stack()
+
+				r := From(req)
+				if r == nil {
+					r = NewWithRequest(w, req)
+				}
+				opts.Logger.Printf("respond: panic serving %s %s (request %q): %v\n%s",
+					req.Method, req.URL.RequestURI(), r.RequestID(), v, stack)
+
+				if tw.written || r.Written() {
+					return
+				}
+				var extra map[string]interface{}
+				if opts.Debug {
+					extra = map[string]interface{}{
+						"panic": fmt.Sprint(v),
+						"stack": string(stack),
+					}
+				}
+				e := ErrInternalServerError
+				if err := r.respondError(r.statusOf(e.Code, e.Status), e.Code, e.Key, nil, extra); err != nil && !errors.Is(err, ErrAlreadyWritten) {
+					opts.Logger.Printf("respond: can not write recovered panic response: %v", err)
+				}
+			}()
+			next.ServeHTTP(tw, req)
+		})
+	}
+}
+
+// trackingWriter records whether the handler has written anything
+type trackingWriter struct {
+	http.ResponseWriter
+	written bool
+}
+
+func (w *trackingWriter) WriteHeader(statusCode int) {
+	w.written = true
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *trackingWriter) Write(b []byte) (int, error) {
+	w.written = true
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *trackingWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		w.written = true
+		f.Flush()
+	}
+}
+
+func (w *trackingWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("respond: response writer does not support hijacking")
+	}
+	w.written = true
+	return h.Hijack()
+}
+
+func (w *trackingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
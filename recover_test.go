@@ -0,0 +1,121 @@
+package respond
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecover(t *testing.T) {
+
+	t.Parallel()
+
+	var logs bytes.Buffer
+	handler := Recover(RecoverOptions{
+		Logger: log.New(&logs, "", 0),
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/users", nil)
+	request.Header.Set("Accept-Language", "fa")
+
+	handler.ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusInternalServerError, recorder.Result().StatusCode)
+	assert.Contains(t, logs.String(), "respond: panic serving GET /users")
+	assert.Contains(t, logs.String(), "boom")
+
+	expected, err := getExpectedMap(recorder.Body)
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"status":  "نا موفق",
+		"error":   float64(5500),
+		"message": ".خطایی در سرور رخ داده است",
+	}, expected)
+}
+
+func TestRecoverWithMiddleware(t *testing.T) {
+
+	t.Parallel()
+
+	var logs bytes.Buffer
+	handler := Middleware(Options{})(Recover(RecoverOptions{
+		Logger: log.New(&logs, "", 0),
+		Debug:  true,
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})))
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("Accept", "application/yaml")
+	request.Header.Set("X-Request-ID", "req-1")
+
+	handler.ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusInternalServerError, recorder.Result().StatusCode)
+	assert.Equal(t, MediaTypeYAML+"; charset=utf-8", recorder.Header().Get("Content-Type"))
+	assert.Contains(t, logs.String(), `(request "req-1")`)
+	assert.Contains(t, recorder.Body.String(), "panic: boom\n")
+	assert.Contains(t, recorder.Body.String(), "stack: ")
+}
+
+func TestRecoverAfterWrite(t *testing.T) {
+
+	t.Parallel()
+
+	handler := Middleware(Options{})(Recover(RecoverOptions{
+		Logger: log.New(&bytes.Buffer{}, "", 0),
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		From(r).NotFound()
+		panic("boom")
+	})))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusNotFound, recorder.Result().StatusCode)
+
+	expected, err := getExpectedMap(recorder.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(5404), expected["error"])
+}
+
+func TestRecoverAfterDirectWrite(t *testing.T) {
+
+	t.Parallel()
+
+	handler := Recover(RecoverOptions{
+		Logger: log.New(&bytes.Buffer{}, "", 0),
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte("partial"))
+		panic("boom")
+	}))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusAccepted, recorder.Result().StatusCode)
+	assert.Equal(t, "partial", recorder.Body.String())
+}
+
+func TestRecoverAbortHandler(t *testing.T) {
+
+	t.Parallel()
+
+	handler := Recover(RecoverOptions{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
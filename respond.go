@@ -2,8 +2,10 @@ package respond
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
+	"time"
 )
 
 type Respond struct {
@@ -12,7 +14,36 @@ type Respond struct {
 	errorCode  int
 	lang       string
 	messages   *Messages
+	local      *Messages
 	writer     http.ResponseWriter
+	request    *http.Request
+	mode       Mode
+	mediaType  string
+	encoder    Encoder
+	written    bool
+	requestID  string
+	challenge  Challenge
+
+	// WWW-Authenticate header of the response and the status it is
+	// written with
+	challengeHeader string
+	challengeStatus int
+
+	etag         string
+	etagMode     ETagMode
+	lastModified time.Time
+	missing      bool
+
+	flushInterval time.Duration
+
+	envelope Envelope
+	statuses StatusProfile
+	encoders []registeredEncoder
+	hooks    Hooks
+
+	// version of the encoders of the config
+	encodersVersion int
+	logger          Logger
 }
 
 // Set language of responses
@@ -31,22 +62,61 @@ func (r *Respond) Language(lang string) *Respond {
 // @since 6 Jun 2021
 // @return *Respond
 func NewWithWriter(w http.ResponseWriter) *Respond {
-	return &Respond{writer: w, messages: NewMessages()}
+	return &Respond{writer: w, messages: defaultMessages, mode: DefaultMode, envelope: DefaultEnvelope}
 }
 
 // Get message type
 //
+// The messages are a copy of the catalog for the response, so changing
+// them changes this response only
+//
 // @author Alireza Josheghani <josheghani.dev@gmail.com>
 // @since 15 Mar 2018
 // @return *Message
 func (r *Respond) Messages() *Messages {
-	if r.lang != "" {
-		r.messages.Lang = r.lang
+	if r.local == nil {
+		r.local = r.messages.clone()
+	}
+	r.local.Lang = r.language()
+	r.local.load()
+	return r.local
+}
+
+// Get the catalog of the response, which is its own copy once Messages
+// is called and the shared catalog before
+func (r *Respond) catalog() *Messages {
+	if r.local != nil {
+		return r.local
 	}
-	r.messages.load()
 	return r.messages
 }
 
+// Get the language of the response, it is negotiated from the
+// Accept-Language header of the request when it is not set
+func (r *Respond) language() string {
+	if r.lang == "" && r.request != nil {
+		r.lang = negotiateLanguage(
+			r.request.Header.Get("Accept-Language"),
+			r.catalog().catalog().supported,
+			DefaultLanguage,
+		)
+	}
+	if r.lang != "" {
+		return r.lang
+	}
+	return r.catalog().language()
+}
+
+// Get the success status text in the language of the response
+func (r *Respond) successText() string {
+	return r.catalog().statusText(r.language(), "success", r.hooks.OnMissing)
+}
+
+// Get the failed status text in the language of the response
+func (r *Respond) failedText() string {
+	return r.catalog().statusText(r.language(), "failed", r.hooks.OnMissing)
+}
+
 // Set status code of response and set default value as 0
 //
 // @author Alireza Josheghani <josheghani.dev@gmail.com>
@@ -80,41 +150,139 @@ func (r *Respond) SetErrorCode(code int) *Respond {
 	return r
 }
 
-// Write json data to http.ResponseWriter
+// Write data to http.ResponseWriter with the encoder negotiated from
+// the Accept header of the request
 //
 // @author Alireza Josheghani <josheghani.dev@gmail.com>
 // @since 6 Jun 2021
-// @param data interface{}
+// @param data interface{}, problem bool
 // @return error
-func (r *Respond) writeJSON(data interface{}) error {
-	b, err := json.Marshal(data)
-	if err != nil {
+func (r *Respond) write(data interface{}, problem bool) error {
+	if r.written {
+		return ErrAlreadyWritten
+	}
+	mediaType, encoder, ok := r.negotiate()
+	if !ok {
+		r.mediaType, r.encoder = MediaTypeJSON, JSONEncoder
+		if err := r.Error(http.StatusNotAcceptable, 5406); err != nil {
+			return err
+		}
+		return ErrNotAcceptable
+	}
+	if problem {
+		mediaType = problemMediaType(mediaType)
+		if mediaType == ProblemXMLContentType {
+			encoder = ProblemXMLEncoder
+		}
+	}
+	body := getBuffer()
+	defer putBuffer(body)
+	if err := encoder.Encode(body, data); err != nil {
+		r.internalError()
+		err = &EncodeError{MediaType: mediaType, Err: err}
+		r.report(err)
 		return err
 	}
-	if _, err := r.writer.Write(b); err != nil {
+	if err := r.flush(mediaType, body.Bytes()); err != nil {
+		r.report(err)
 		return err
 	}
-	r.writer.Header().Set("content-type", "application/json")
 	return nil
 }
 
-// Pass response with result data like this array
+// Write the headers, the status and then the encoded body, so nothing
+// is sent before the body is completely encoded. A successful response
+// which the client already has is written as 304 Not Modified
+func (r *Respond) flush(mediaType string, body []byte) error {
+	r.written = true
+	if r.notModified(body) {
+		r.writer.WriteHeader(http.StatusNotModified)
+		r.responded(http.StatusNotModified)
+		return nil
+	}
+	header := r.writer.Header()
+	header.Set("Content-Type", contentType(mediaType))
+	header.Set("Content-Length", strconv.Itoa(len(body)))
+	r.writeChallenge(header)
+	r.writer.WriteHeader(r.statusCode)
+	_, err := r.writer.Write(body)
+	r.responded(r.statusCode)
+	return err
+}
+
+// Call the OnResponse hook with the written status
+func (r *Respond) responded(statusCode int) {
+	if r.hooks.OnResponse != nil {
+		r.hooks.OnResponse(r, statusCode)
+	}
+}
+
+// Respond with a JSON internal server error, used when the body of a
+// response can not be encoded and nothing is written yet
+func (r *Respond) internalError() error {
+	message, _ := r.translate(ErrInternalServerError.Key, nil)
+	r.SetStatusCode(ErrInternalServerError.Status).
+		SetStatusText(r.failedText()).
+		SetErrorCode(ErrInternalServerError.Code)
+	mediaType := MediaTypeJSON
+	data := r.messageData(message, nil)
+	if r.mode == ModeProblem {
+		mediaType = ProblemContentType
+		data = r.problem(ErrInternalServerError.Status, ErrInternalServerError.Code, message)
+	}
+	body, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return r.flush(mediaType, body)
+}
+
+// Whether the response is already written
+//
+// @return bool
+func (r *Respond) Written() bool {
+	return r.written
+}
+
+// Get the media type and the encoder of the response
+func (r *Respond) negotiate() (string, Encoder, bool) {
+	if r.encoder != nil {
+		return r.mediaType, r.encoder, true
+	}
+	accept := ""
+	if r.request != nil {
+		accept = r.request.Header.Get("Accept")
+	}
+	var mediaType string
+	var encoder Encoder
+	var ok bool
+	if r.encoders != nil {
+		mediaType, encoder, ok = negotiateEncoder(r.encoders, accept)
+	} else {
+		mediaType, encoder, ok = NegotiateEncoder(accept)
+	}
+	if ok {
+		r.mediaType, r.encoder = mediaType, encoder
+	}
+	return mediaType, encoder, ok
+}
+
+// Pass response with result data like this array, the keys are the
+// keys of the envelope of the response
 //
-//      array := map[string]interface{} {
-//        "status": respond.statusText,
-//        "result": result,
-//      }
+//	array := map[string]interface{} {
+//	  "status": respond.statusText,
+//	  "result": result,
+//	}
 //
 // @author Alireza Josheghani <josheghani.dev@gmail.com>
 // @since 15 Mar 2018
 // @param result map[string]interface{}
 // @return error
-func (r *Respond) RespondWithResult(result interface{}) {
-	r.writer.WriteHeader(r.statusCode)
-	r.writeJSON(map[string]interface{}{
-		"status": r.statusText,
-		"result": result,
-	})
+func (r *Respond) RespondWithResult(result interface{}) error {
+	data := r.envelopeData()
+	data.Result, data.HasResult = result, true
+	return r.write(r.envelope.Build(data), false)
 }
 
 // Pass response with message text as string
@@ -123,39 +291,71 @@ func (r *Respond) RespondWithResult(result interface{}) {
 // @since 15 Mar 2018
 // @param message interface{}
 // @return error
-func (r *Respond) RespondWithMessage(message interface{}) {
-	data := map[string]interface{}{
-		"status":  r.statusText,
-		"message": message,
+func (r *Respond) RespondWithMessage(message interface{}) error {
+	return r.write(r.messageData(message, nil), false)
+}
+
+// Respond with a message of the catalog, the responses of messages
+// without arguments are written from the cache of pre-encoded bodies
+func (r *Respond) respondMessage(statusCode int, success bool, key string, args Args) error {
+	if args == nil {
+		if ok, err := r.writeStatic(key, statusCode, success); ok {
+			return err
+		}
 	}
-	if r.errorCode != 0 {
-		data["error"] = r.errorCode
+	message, missing := r.translate(key, args)
+	statusText := r.failedText()
+	if success {
+		statusText = r.successText()
+	}
+	err := r.SetStatusCode(statusCode).
+		SetStatusText(statusText).
+		RespondWithMessage(message)
+	if err != nil {
+		return err
+	}
+	return missing
+}
+
+// Wrap a message and the extra members in the envelope
+func (r *Respond) messageData(message interface{}, extra map[string]interface{}) interface{} {
+	data := r.envelopeData()
+	data.Message, data.HasMessage = message, true
+	data.Extra = extra
+	return r.envelope.Build(data)
+}
+
+// Get the envelope data of the status of the response
+func (r *Respond) envelopeData() EnvelopeData {
+	return EnvelopeData{
+		Success: r.statusCode < http.StatusBadRequest,
+		Status:  r.statusText,
+		Code:    r.errorCode,
 	}
-	r.writer.WriteHeader(r.statusCode)
-	r.writeJSON(data)
 }
 
 // return notfound result
 //
 // @author Alireza Josheghani <josheghani.dev@gmail.com>
 // @since 15 Mar 2018
-// @return (statuscode int, result interface{})
-func (r *Respond) NotFound() {
-	r.Error(404, 5404)
+// @return error
+func (r *Respond) NotFound() error {
+	return r.catalogError(404, 5404)
 }
 
 // return success result with data
 //
-//      data := map[string]interface{} {
-//        "data": "somedata"
-//      }
+//	data := map[string]interface{} {
+//	  "data": "somedata"
+//	}
 //
 // @author Alireza Josheghani <josheghani.dev@gmail.com>
 // @since 15 Mar 2018
 // @param data map[string]interface{}
-func (r *Respond) Succeed(data interface{}) {
-	r.SetStatusCode(http.StatusOK).
-		SetStatusText(r.Messages().Success).
+// @return error
+func (r *Respond) Succeed(data interface{}) error {
+	return r.SetStatusCode(http.StatusOK).
+		SetStatusText(r.successText()).
 		RespondWithResult(data)
 }
 
@@ -163,128 +363,218 @@ func (r *Respond) Succeed(data interface{}) {
 //
 // @author Alireza Josheghani <josheghani.dev@gmail.com>
 // @since 15 Mar 2018
-func (r *Respond) InsertSucceeded() {
-	message := r.Messages().Errors["success"]
-	r.SetStatusCode(http.StatusOK).
-		SetStatusText(r.Messages().Success).
-		RespondWithMessage(message["insert"])
+// @param args ...Args
+// @return error
+func (r *Respond) InsertSucceeded(args ...Args) error {
+	return r.respondMessage(http.StatusOK, true, "errors.success.insert", mergeArgs(args))
 }
 
 // Insert action is failed
 //
 // @author Alireza Josheghani <josheghani.dev@gmail.com>
 // @since 15 Mar 2018
-func (r *Respond) InsertFailed() {
-	message := r.Messages().Errors["failed"]
-	r.SetStatusCode(448).
-		SetStatusText(r.Messages().Failed).
-		RespondWithMessage(message["insert"])
+// @param args ...Args
+// @return error
+func (r *Respond) InsertFailed(args ...Args) error {
+	return r.respondMessage(r.statusOf(5448, 448), false, "errors.failed.insert", mergeArgs(args))
 }
 
 // Delete action is succeed
 //
 // @author Alireza Josheghani <josheghani.dev@gmail.com>
 // @since 15 Mar 2018
-// @return (statuscode int, result interface{})
-func (r *Respond) DeleteSucceeded() {
-	message := r.Messages().Errors["success"]
-	r.SetStatusCode(200).
-		SetStatusText(r.Messages().Success).
-		RespondWithMessage(message["delete"])
+// @param args ...Args
+// @return error
+func (r *Respond) DeleteSucceeded(args ...Args) error {
+	return r.respondMessage(200, true, "errors.success.delete", mergeArgs(args))
 }
 
 // Delete action is failed
 //
 // @author Alireza Josheghani <josheghani.dev@gmail.com>
 // @since 15 Mar 2018
-// @return (statuscode int, result interface{})
-func (r *Respond) DeleteFailed() {
-	message := r.Messages().Errors["failed"]
-	r.SetStatusCode(447).
-		SetStatusText(r.Messages().Failed).
-		RespondWithMessage(message["delete"])
+// @param args ...Args
+// @return error
+func (r *Respond) DeleteFailed(args ...Args) error {
+	return r.respondMessage(r.statusOf(5447, 447), false, "errors.failed.delete", mergeArgs(args))
 }
 
 // Update action is succeed
 //
 // @author Alireza Josheghani <josheghani.dev@gmail.com>
 // @since 15 Mar 2018
-// @return (statuscode int, result interface{})
-func (r *Respond) UpdateSucceeded() {
-	message := r.Messages().Errors["success"]
-	r.SetStatusCode(200).
-		SetStatusText(r.Messages().Success).
-		RespondWithMessage(message["update"])
+// @param args ...Args
+// @return error
+func (r *Respond) UpdateSucceeded(args ...Args) error {
+	return r.respondMessage(200, true, "errors.success.update", mergeArgs(args))
 }
 
 // Update action is failed
 //
 // @author Alireza Josheghani <josheghani.dev@gmail.com>
 // @since 15 Mar 2018
-// @return (statuscode int, result interface{})
-func (r *Respond) UpdateFailed() {
-	message := r.Messages().Errors["failed"]
-	r.SetStatusCode(449).
-		SetStatusText(r.Messages().Failed).
-		RespondWithMessage(message["update"])
+// @param args ...Args
+// @return error
+func (r *Respond) UpdateFailed(args ...Args) error {
+	return r.respondMessage(r.statusOf(5449, 449), false, "errors.failed.update", mergeArgs(args))
 }
 
 // Wrong parameters are entered
 //
 // @author Alireza Josheghani <josheghani.dev@gmail.com>
 // @since 15 Mar 2018
-func (r *Respond) WrongParameters() {
-	r.Error(406, 5406)
+// @return error
+func (r *Respond) WrongParameters() error {
+	return r.catalogError(406, 5406)
 }
 
 // Wrong parameters are entered
 //
 // @author Alireza Josheghani <josheghani.dev@gmail.com>
 // @since 15 Mar 2018
-func (r *Respond) MethodNotAllowed() {
-	r.Error(405, 5405)
+// @return error
+func (r *Respond) MethodNotAllowed() error {
+	return r.catalogError(405, 5405)
 }
 
 // There ara validation translations
 //
+// A *Validation, a []*FieldError or an error of a validator library
+// known to the registered adapters is sent as a list of field errors with
+// localised messages with the code and the message of 5420, other
+// values are sent as the result as they are
+//
+//	// {"status":"failed","error":5420,"message":"Validation Error","result":[{"field":"/email","rule":"required","message":"The email field is required"}]}
+//
 // @author Alireza Josheghani <josheghani.dev@gmail.com>
 // @since 15 Mar 2018
 // @param translations map[string]interface{}
-func (r *Respond) ValidationErrors(errors interface{}) {
-	r.SetStatusCode(420).
-		SetStatusText(r.Messages().Failed).
-		SetErrorCode(5420).
-		RespondWithResult(errors)
+// @return error
+func (r *Respond) ValidationErrors(errors interface{}) error {
+	errors = r.fieldErrors(errors)
+	statusCode := r.statusOf(5420, 420)
+	if r.mode == ModeProblem {
+		message, missing := r.translate("errors.5420.message", nil)
+		problem := r.problem(statusCode, 5420, message)
+		problem["errors"] = errors
+		if err := r.SetStatusCode(statusCode).RespondWithProblem(problem); err != nil {
+			return err
+		}
+		return missing
+	}
+	r.SetStatusCode(statusCode).
+		SetStatusText(r.failedText()).
+		SetErrorCode(5420)
+	data := r.envelopeData()
+	data.Details = errors
+	if _, ok := errors.([]*FieldError); ok {
+		message, missing := r.translate("errors.5420.message", nil)
+		data.Message, data.HasMessage = message, true
+		if err := r.write(r.envelope.Build(data), false); err != nil {
+			return err
+		}
+		return missing
+	}
+	return r.write(r.envelope.Build(data), false)
+}
+
+// Something went wrong on the server
+//
+// @return error
+func (r *Respond) InternalServerError() error {
+	return r.catalogError(http.StatusInternalServerError, 5500)
 }
 
 // The request field is not found
 //
 // @author Alireza Josheghani <josheghani.dev@gmail.com>
 // @since 15 Mar 2018
-// @return (statuscode int, result interface{})
-func (r *Respond) RequestFieldNotfound() {
-	r.Error(446, 1001)
+// @param args ...Args
+// @return error
+func (r *Respond) RequestFieldNotfound(args ...Args) error {
+	return r.catalogError(446, 1001, args...)
 }
 
 // The request field is duplicated
 //
 // @author Alireza Josheghani <josheghani.dev@gmail.com>
 // @since 15 Mar 2018
-// @return (statuscode int, result interface{})
-func (r *Respond) RequestFieldDuplicated() {
-	r.Error(400, 1004)
+// @param args ...Args
+// @return error
+func (r *Respond) RequestFieldDuplicated(args ...Args) error {
+	return r.catalogError(400, 1004, args...)
 }
 
 // The error message
 //
 // @author Alireza Josheghani <josheghani.dev@gmail.com>
 // @since 15 Mar 2018
-// @param statusCode int,errorCode string
-// @return (statuscode int, result interface{})
-func (r *Respond) Error(statusCode int, errorCode int) {
-	message := r.Messages().Errors[strconv.Itoa(errorCode)]
+// @param statusCode int, errorCode int, args ...Args
+// @return error
+func (r *Respond) Error(statusCode int, errorCode int, args ...Args) error {
+	return r.respondError(statusCode, errorCode, "errors."+strconv.Itoa(errorCode)+".message", mergeArgs(args), nil)
+}
+
+// Respond with any error, a *Error anywhere in the chain of err is
+// written with its status and localised message and every other error
+// is written as an internal server error. Nothing is written for nil
+//
+//	if err := users.Find(id); err != nil {
+//	  return r.Err(err)
+//	}
+//
+// @param err error
+// @return error
+func (r *Respond) Err(err error) error {
+	if err == nil {
+		return nil
+	}
+	var e *Error
+	if !errors.As(err, &e) {
+		e = ErrInternalServerError
+	}
+	return r.respondError(r.statusOf(e.Code, e.Status), e.Code, e.Key, nil, nil)
+}
+
+// Respond with a catalogued error, the extra members are added to the
+// envelope or the problem document
+func (r *Respond) respondError(statusCode, errorCode int, key string, args Args, extra map[string]interface{}) error {
+	if r.written {
+		return ErrAlreadyWritten
+	}
+	r.SetErrorCode(errorCode)
+	r.prepareChallenge(statusCode, errorCode, key, args)
+	if args == nil && extra == nil {
+		if ok, err := r.writeStatic(key, statusCode, false); ok {
+			return err
+		}
+	}
+	message, missing := r.translate(key, args)
 	r.SetStatusCode(statusCode).
-		SetStatusText(r.Messages().Failed).
-		SetErrorCode(errorCode).
-		RespondWithMessage(message["message"])
+		SetStatusText(r.failedText())
+	var data interface{}
+	if r.mode == ModeProblem {
+		problem := r.problem(statusCode, errorCode, message)
+		for k, v := range extra {
+			problem[k] = v
+		}
+		data = problem
+	} else {
+		data = r.messageData(message, extra)
+	}
+	if err := r.write(data, r.mode == ModeProblem); err != nil {
+		return err
+	}
+	return missing
+}
+
+// Translate a message key in the language of the response, the key
+// itself is used when the translation is missing so the response is
+// still written and the error of the translation is returned with it
+func (r *Respond) translate(key string, args Args) (string, error) {
+	message, err := r.catalog().translate(r.language(), key, args, r.hooks.OnMissing)
+	if err != nil {
+		return key, err
+	}
+	return message, nil
 }
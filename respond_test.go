@@ -2,9 +2,11 @@ package respond
 
 import (
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -26,7 +28,7 @@ func TestNotFound(t *testing.T) {
 	NewWithWriter(recorder).NotFound()
 
 	assert.Equal(t, http.StatusNotFound, recorder.Result().StatusCode)
-	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, "application/json; charset=utf-8", recorder.Header().Get("Content-Type"))
 
 	expected, err := getExpectedMap(recorder.Body)
 	assert.NoError(t, err)
@@ -49,7 +51,7 @@ func TestSucceed(t *testing.T) {
 	})
 
 	assert.Equal(t, http.StatusOK, recorder.Result().StatusCode)
-	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, "application/json; charset=utf-8", recorder.Header().Get("Content-Type"))
 
 	expected, err := getExpectedMap(recorder.Body)
 	assert.NoError(t, err)
@@ -70,7 +72,7 @@ func TestInsertSucceeded(t *testing.T) {
 	NewWithWriter(recorder).InsertSucceeded()
 
 	assert.Equal(t, http.StatusOK, recorder.Result().StatusCode)
-	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, "application/json; charset=utf-8", recorder.Header().Get("Content-Type"))
 
 	expected, err := getExpectedMap(recorder.Body)
 	assert.NoError(t, err)
@@ -89,7 +91,7 @@ func TestInsertFailed(t *testing.T) {
 	NewWithWriter(recorder).InsertFailed()
 
 	assert.Equal(t, 448, recorder.Result().StatusCode)
-	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, "application/json; charset=utf-8", recorder.Header().Get("Content-Type"))
 
 	expected, err := getExpectedMap(recorder.Body)
 	assert.NoError(t, err)
@@ -108,7 +110,7 @@ func TestDeleteSucceeded(t *testing.T) {
 	NewWithWriter(recorder).DeleteSucceeded()
 
 	assert.Equal(t, http.StatusOK, recorder.Result().StatusCode)
-	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, "application/json; charset=utf-8", recorder.Header().Get("Content-Type"))
 
 	expected, err := getExpectedMap(recorder.Body)
 	assert.NoError(t, err)
@@ -127,7 +129,7 @@ func TestDeleteFailed(t *testing.T) {
 	NewWithWriter(recorder).DeleteFailed()
 
 	assert.Equal(t, 447, recorder.Result().StatusCode)
-	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, "application/json; charset=utf-8", recorder.Header().Get("Content-Type"))
 
 	expected, err := getExpectedMap(recorder.Body)
 	assert.NoError(t, err)
@@ -146,7 +148,7 @@ func TestUpdateSucceeded(t *testing.T) {
 	NewWithWriter(recorder).UpdateSucceeded()
 
 	assert.Equal(t, http.StatusOK, recorder.Result().StatusCode)
-	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, "application/json; charset=utf-8", recorder.Header().Get("Content-Type"))
 
 	expected, err := getExpectedMap(recorder.Body)
 	assert.NoError(t, err)
@@ -165,7 +167,7 @@ func TestUpdateFailed(t *testing.T) {
 	NewWithWriter(recorder).UpdateFailed()
 
 	assert.Equal(t, 449, recorder.Result().StatusCode)
-	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, "application/json; charset=utf-8", recorder.Header().Get("Content-Type"))
 
 	expected, err := getExpectedMap(recorder.Body)
 	assert.NoError(t, err)
@@ -184,7 +186,7 @@ func TestWrongParameters(t *testing.T) {
 	NewWithWriter(recorder).WrongParameters()
 
 	assert.Equal(t, 406, recorder.Result().StatusCode)
-	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, "application/json; charset=utf-8", recorder.Header().Get("Content-Type"))
 
 	expected, err := getExpectedMap(recorder.Body)
 	assert.NoError(t, err)
@@ -204,7 +206,7 @@ func TestMethodNotAllowed(t *testing.T) {
 	NewWithWriter(recorder).MethodNotAllowed()
 
 	assert.Equal(t, 405, recorder.Result().StatusCode)
-	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, "application/json; charset=utf-8", recorder.Header().Get("Content-Type"))
 
 	expected, err := getExpectedMap(recorder.Body)
 	assert.NoError(t, err)
@@ -226,7 +228,7 @@ func TestValidationErrors(t *testing.T) {
 	})
 
 	assert.Equal(t, 420, recorder.Result().StatusCode)
-	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, "application/json; charset=utf-8", recorder.Header().Get("Content-Type"))
 
 	expected, err := getExpectedMap(recorder.Body)
 	assert.NoError(t, err)
@@ -247,7 +249,7 @@ func TestRequestFieldNotfound(t *testing.T) {
 	NewWithWriter(recorder).RequestFieldNotfound()
 
 	assert.Equal(t, 446, recorder.Result().StatusCode)
-	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, "application/json; charset=utf-8", recorder.Header().Get("Content-Type"))
 
 	expected, err := getExpectedMap(recorder.Body)
 	assert.NoError(t, err)
@@ -267,7 +269,7 @@ func TestRequestFieldDuplicated(t *testing.T) {
 	NewWithWriter(recorder).RequestFieldDuplicated()
 
 	assert.Equal(t, http.StatusBadRequest, recorder.Result().StatusCode)
-	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, "application/json; charset=utf-8", recorder.Header().Get("Content-Type"))
 
 	expected, err := getExpectedMap(recorder.Body)
 	assert.NoError(t, err)
@@ -287,7 +289,7 @@ func TestDefaultWithLang(t *testing.T) {
 	NewWithWriter(recorder).Language("fa").NotFound()
 
 	assert.Equal(t, http.StatusNotFound, recorder.Result().StatusCode)
-	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, "application/json; charset=utf-8", recorder.Header().Get("Content-Type"))
 
 	expected, err := getExpectedMap(recorder.Body)
 	assert.NoError(t, err)
@@ -323,7 +325,7 @@ func TestCustomLanguage(t *testing.T) {
 	respond.Language("ru").NotFound()
 
 	assert.Equal(t, http.StatusNotFound, recorder.Result().StatusCode)
-	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, "application/json; charset=utf-8", recorder.Header().Get("Content-Type"))
 
 	expected, err := getExpectedMap(recorder.Body)
 	assert.NoError(t, err)
@@ -335,3 +337,113 @@ func TestCustomLanguage(t *testing.T) {
 	}, expected)
 
 }
+
+func TestAlreadyWritten(t *testing.T) {
+
+	t.Parallel()
+
+	var (
+		recorder = httptest.NewRecorder()
+		respond  = NewWithWriter(recorder)
+	)
+
+	assert.False(t, respond.Written())
+	assert.NoError(t, respond.NotFound())
+	assert.True(t, respond.Written())
+	assert.Equal(t, ErrAlreadyWritten, respond.Succeed("Test"))
+
+	assert.Equal(t, http.StatusNotFound, recorder.Result().StatusCode)
+
+	expected, err := getExpectedMap(recorder.Body)
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"message": "Oops... The requested page not found!",
+		"status":  "failed",
+		"error":   float64(5404),
+	}, expected)
+}
+
+func TestEncodeError(t *testing.T) {
+
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+	err := NewWithWriter(recorder).Succeed(make(chan int))
+
+	assert.True(t, errors.Is(err, ErrEncode))
+
+	var encodeError *EncodeError
+	assert.True(t, errors.As(err, &encodeError))
+	assert.Equal(t, MediaTypeJSON, encodeError.MediaType)
+
+	assert.Equal(t, http.StatusInternalServerError, recorder.Result().StatusCode)
+	assert.Equal(t, "application/json; charset=utf-8", recorder.Header().Get("Content-Type"))
+
+	expected, err := getExpectedMap(recorder.Body)
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"message": "Oops... Something went wrong on our side!",
+		"status":  "failed",
+		"error":   float64(5500),
+	}, expected)
+}
+
+func TestMissingTranslationReturned(t *testing.T) {
+
+	t.Parallel()
+
+	var (
+		recorder = httptest.NewRecorder()
+		respond  = NewWithWriter(recorder)
+	)
+
+	respond.Messages().MissingPolicy = MissingError
+	err := respond.Error(400, 9999)
+
+	assert.Equal(t, &MissingTranslationError{Lang: "en", Key: "errors.9999.message"}, err)
+	assert.Equal(t, http.StatusBadRequest, recorder.Result().StatusCode)
+}
+
+func TestHeadersSentWithStatus(t *testing.T) {
+
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		NewWithRequest(w, r).NotFound()
+	}))
+	defer server.Close()
+
+	response, err := http.Get(server.URL)
+	assert.NoError(t, err)
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	assert.NoError(t, err)
+
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+	assert.Equal(t, "application/json; charset=utf-8", response.Header.Get("Content-Type"))
+	assert.Equal(t, strconv.Itoa(len(body)), response.Header.Get("Content-Length"))
+	assert.JSONEq(t, `{"status":"failed","message":"Oops... The requested page not found!","error":5404}`, string(body))
+}
+
+func TestEncodeErrorProblem(t *testing.T) {
+
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+	err := NewWithWriter(recorder).SetMode(ModeProblem).Succeed(func() {})
+
+	assert.True(t, errors.Is(err, ErrEncode))
+	assert.Equal(t, http.StatusInternalServerError, recorder.Result().StatusCode)
+	assert.Equal(t, ProblemContentType+"; charset=utf-8", recorder.Result().Header.Get("Content-Type"))
+	assert.JSONEq(t, `{
+		"type": "about:blank",
+		"title": "Internal Server Error",
+		"status": 500,
+		"detail": "Oops... Something went wrong on our side!",
+		"code": 5500,
+		"short": "internal-server-error"
+	}`, recorder.Body.String())
+}
@@ -0,0 +1,203 @@
+package respond
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MediaTypeEventStream is the media type of Server-Sent Events
+const MediaTypeEventStream = "text/event-stream"
+
+// Event holds the fields of a Server-Sent Event besides its data, the
+// empty ones are left out
+type Event struct {
+	// Name of the event, the message event of browsers is used when it
+	// is empty
+	Name string
+
+	// ID of the event, browsers send the last one in the Last-Event-ID
+	// header when they reconnect
+	ID string
+
+	// Retry is the reconnection time of browsers
+	Retry time.Duration
+}
+
+// EventStream writes Server-Sent Events whose data is the envelope of
+// the normal responses, see Respond.EventStream
+type EventStream struct {
+	r       *Respond
+	ctx     context.Context
+	flusher http.Flusher
+	err     error
+}
+
+// Start a Server-Sent Events stream, the headers are written right away
+// and the stream stops when the context is done
+//
+//      events, err := r.EventStream(req.Context())
+//      if err != nil {
+//        return err
+//      }
+//      for progress := range job.Progress() {
+//        if err := events.Result(respond.Event{Name: "progress"}, progress); err != nil {
+//          return err
+//        }
+//      }
+//
+// @param ctx context.Context
+// @return (*EventStream, error)
+func (r *Respond) EventStream(ctx context.Context) (*EventStream, error) {
+	if r.written {
+		return nil, ErrAlreadyWritten
+	}
+	r.written = true
+	r.SetStatusCode(http.StatusOK)
+
+	header := r.writer.Header()
+	header.Del("Content-Length")
+	header.Set("Content-Type", contentType(MediaTypeEventStream))
+	header.Set("Cache-Control", "no-cache")
+	header.Set("X-Accel-Buffering", "no")
+	r.writer.WriteHeader(r.statusCode)
+
+	s := &EventStream{r: r, ctx: ctx}
+	s.flusher, _ = r.writer.(http.Flusher)
+	if s.flusher != nil {
+		s.flusher.Flush()
+	}
+	return s, nil
+}
+
+// Get the ID of the last event the client got before it reconnected
+//
+// @return string
+func (s *EventStream) LastEventID() string {
+	if s.r.request == nil {
+		return ""
+	}
+	return s.r.request.Header.Get("Last-Event-ID")
+}
+
+// Send a result like Succeed does
+//
+// @param event Event, result interface{}
+// @return error
+func (s *EventStream) Result(event Event, result interface{}) error {
+	return s.Send(event, s.r.envelope.Build(EnvelopeData{
+		Success:   true,
+		Status:    s.r.successText(),
+		Result:    result,
+		HasResult: true,
+	}))
+}
+
+// Send a localised message of the catalog with the success status
+//
+//      events.Message(respond.Event{Name: "done"}, "errors.success.insert")
+//
+// @param event Event, key string, args ...Args
+// @return error
+func (s *EventStream) Message(event Event, key string, args ...Args) error {
+	message, missing := s.r.translate(key, mergeArgs(args))
+	if err := s.Send(event, s.r.envelope.Build(EnvelopeData{
+		Success:    true,
+		Status:     s.r.successText(),
+		Message:    message,
+		HasMessage: true,
+	})); err != nil {
+		return err
+	}
+	return missing
+}
+
+// Send an error like Respond.Err does, a *Error anywhere in the chain of
+// err is sent with its localised message and every other error as an
+// internal server error
+//
+// @param event Event, err error
+// @return error
+func (s *EventStream) Error(event Event, err error) error {
+	var e *Error
+	if !errors.As(err, &e) {
+		e = ErrInternalServerError
+	}
+	message, missing := s.r.translate(e.Key, nil)
+	if err := s.Send(event, s.r.envelope.Build(EnvelopeData{
+		Status:     s.r.failedText(),
+		Code:       e.Code,
+		Message:    message,
+		HasMessage: true,
+	})); err != nil {
+		return err
+	}
+	return missing
+}
+
+// Send an event with any data encoded as JSON, nothing is sent once the
+// context is done and its error is returned
+//
+// @param event Event, data interface{}
+// @return error
+func (s *EventStream) Send(event Event, data interface{}) error {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return &EncodeError{MediaType: MediaTypeEventStream, Err: err}
+	}
+	frame := getBuffer()
+	defer putBuffer(frame)
+	if event.Name != "" {
+		frame.WriteString("event: " + eventField(event.Name) + "\n")
+	}
+	if event.ID != "" {
+		frame.WriteString("id: " + eventField(strings.ReplaceAll(event.ID, "\x00", "")) + "\n")
+	}
+	if event.Retry > 0 {
+		frame.WriteString("retry: " + strconv.FormatInt(event.Retry.Milliseconds(), 10) + "\n")
+	}
+	frame.WriteString("data: ")
+	frame.Write(b)
+	frame.WriteString("\n\n")
+	return s.write(frame.Bytes())
+}
+
+// Send a comment, which browsers ignore, to keep the connection alive
+//
+// @param text string
+// @return error
+func (s *EventStream) Comment(text string) error {
+	return s.write([]byte(": " + eventField(text) + "\n\n"))
+}
+
+// Get a channel which is closed when the stream is stopped
+//
+// @return <-chan struct{}
+func (s *EventStream) Done() <-chan struct{} {
+	return s.ctx.Done()
+}
+
+func (s *EventStream) write(frame []byte) error {
+	if s.err != nil {
+		return s.err
+	}
+	if s.err = s.ctx.Err(); s.err != nil {
+		return s.err
+	}
+	if _, s.err = s.r.writer.Write(frame); s.err != nil {
+		return s.err
+	}
+	if s.flusher != nil {
+		s.flusher.Flush()
+	}
+	return nil
+}
+
+// Fields of events are single lines
+func eventField(value string) string {
+	return strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ").Replace(value)
+}
@@ -0,0 +1,85 @@
+package respond
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventStream(t *testing.T) {
+
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/events", nil)
+	req.Header.Set("Last-Event-ID", "41")
+
+	events, err := NewWithRequest(recorder, req).EventStream(req.Context())
+	assert.NoError(t, err)
+	assert.Equal(t, "41", events.LastEventID())
+	assert.True(t, recorder.Flushed)
+
+	assert.NoError(t, events.Result(Event{Name: "progress", ID: "42", Retry: 3 * time.Second}, map[string]int{"done": 10}))
+	assert.NoError(t, events.Message(Event{Name: "done"}, "errors.success.insert"))
+	assert.NoError(t, events.Error(Event{}, fmt.Errorf("job: %w", ErrTokenExpired)))
+	assert.NoError(t, events.Comment("keep\nalive"))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "text/event-stream; charset=utf-8", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, "no-cache", recorder.Header().Get("Cache-Control"))
+	assert.Equal(t, "event: progress\nid: 42\nretry: 3000\n"+`data: {"result":{"done":10},"status":"success"}`+"\n\n"+
+		"event: done\n"+`data: {"message":"The requested parameter is added successfully!","status":"success"}`+"\n\n"+
+		`data: {"error":3010,"message":"Token expired!","status":"failed"}`+"\n\n"+
+		": keep alive\n\n", recorder.Body.String())
+}
+
+func TestEventStreamLanguage(t *testing.T) {
+
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+	events, err := NewWithWriter(recorder).Language("fa").EventStream(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, events.LastEventID())
+
+	assert.NoError(t, events.Error(Event{Name: "failed\r\nid: 1"}, errors.New("boom")))
+	assert.Equal(t, "event: failed id: 1\n"+`data: {"error":5500,"message":".خطایی در سرور رخ داده است","status":"نا موفق"}`+"\n\n", recorder.Body.String())
+}
+
+func TestEventStreamCanceled(t *testing.T) {
+
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	recorder := httptest.NewRecorder()
+	events, err := NewWithWriter(recorder).EventStream(ctx)
+	assert.NoError(t, err)
+
+	assert.NoError(t, events.Result(Event{}, 1))
+	cancel()
+	<-events.Done()
+	assert.Equal(t, context.Canceled, events.Result(Event{}, 2))
+	assert.Equal(t, context.Canceled, events.Comment("ping"))
+	assert.Equal(t, "data: {\"result\":1,\"status\":\"success\"}\n\n", recorder.Body.String())
+}
+
+func TestEventStreamErrors(t *testing.T) {
+
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+	r := NewWithWriter(recorder)
+	events, err := r.EventStream(context.Background())
+	assert.NoError(t, err)
+
+	assert.True(t, errors.Is(events.Send(Event{}, func() {}), ErrEncode))
+
+	_, err = r.EventStream(context.Background())
+	assert.Equal(t, ErrAlreadyWritten, err)
+}
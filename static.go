@@ -0,0 +1,116 @@
+package respond
+
+// The settings a response of a fixed message depends on
+type staticKey struct {
+	lang      string
+	key       string
+	status    int
+	code      int
+	mediaType string
+	version   int
+	xmlRoot   string
+	envelope  envelopeKey
+}
+
+// The fields of an Envelope without Wrap, so they can be compared
+type envelopeKey struct {
+	statusKey     string
+	boolStatus    bool
+	resultKey     string
+	messageKey    string
+	metaKey       string
+	errorKey      string
+	nestError     bool
+	codeKey       string
+	detailsKey    string
+	omitNilResult bool
+}
+
+// A pre-encoded response body
+type staticBody struct {
+	mediaType string
+	body      []byte
+}
+
+func (e Envelope) key() envelopeKey {
+	return envelopeKey{
+		statusKey:     e.StatusKey,
+		boolStatus:    e.BoolStatus,
+		resultKey:     e.ResultKey,
+		messageKey:    e.MessageKey,
+		metaKey:       e.MetaKey,
+		errorKey:      e.ErrorKey,
+		nestError:     e.NestError,
+		codeKey:       e.CodeKey,
+		detailsKey:    e.DetailsKey,
+		omitNilResult: e.OmitNilResult,
+	}
+}
+
+// Write a response of a fixed message from the cache of the catalog, the
+// body is encoded on the first use of every language, format, status and
+// envelope, and again when the encoders or XMLRootElement change. A new
+// catalog starts with an empty cache, so the cache is dropped when the
+// translations change. It reports whether the response is written,
+// responses which are not fixed are left to the caller
+func (r *Respond) writeStatic(key string, statusCode int, success bool) (bool, error) {
+	if r.written || r.mode == ModeProblem || r.envelope.Wrap != nil {
+		return false, nil
+	}
+	c := r.catalog().catalog()
+	lang := r.language()
+	view, ok := c.views[lang]
+	if !ok {
+		return false, nil
+	}
+	statusText := view.failed
+	if success {
+		statusText = view.success
+	}
+	if statusText == "" {
+		return false, nil
+	}
+	message, l, ok := c.findIn(view.chain, key, nil)
+	if !ok {
+		return false, nil
+	}
+	mediaType, encoder, ok := r.negotiate()
+	if !ok {
+		return false, nil
+	}
+
+	k := staticKey{
+		lang:      lang,
+		key:       key,
+		status:    statusCode,
+		code:      r.errorCode,
+		mediaType: mediaType,
+		version:   r.encodersVersion,
+		xmlRoot:   XMLRootElement,
+		envelope:  r.envelope.key(),
+	}
+	if r.encoders == nil {
+		k.version = encodersVersion()
+	}
+	r.SetStatusCode(statusCode).SetStatusText(statusText)
+
+	cached, ok := c.static.Load(k)
+	if !ok {
+		body := getBuffer()
+		defer putBuffer(body)
+		if err := encoder.Encode(body, r.messageData(interpolate(message, l, nil), nil)); err != nil {
+			return false, nil
+		}
+		cached = &staticBody{
+			mediaType: mediaType,
+			body:      append([]byte(nil), body.Bytes()...),
+		}
+		c.static.Store(k, cached)
+	}
+	static := cached.(*staticBody)
+	if err := r.flush(static.mediaType, static.body); err != nil {
+		r.report(err)
+		return true, err
+	}
+	return true, nil
+}
@@ -0,0 +1,197 @@
+package respond
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Number of the pre-encoded responses of a catalog
+func staticCount(m *Messages) int {
+	count := 0
+	m.catalog().static.Range(func(key, value interface{}) bool {
+		count++
+		return true
+	})
+	return count
+}
+
+func TestStaticResponses(t *testing.T) {
+
+	t.Parallel()
+
+	config := New()
+	helpers := []func(r *Respond, args ...Args) error{
+		(*Respond).InsertSucceeded,
+		(*Respond).InsertFailed,
+		(*Respond).DeleteSucceeded,
+		(*Respond).DeleteFailed,
+		(*Respond).UpdateSucceeded,
+		(*Respond).UpdateFailed,
+		func(r *Respond, args ...Args) error { return r.Error(http.StatusNotFound, 5404, args...) },
+	}
+	for _, lang := range []string{"en", "fa"} {
+		for _, accept := range []string{"application/json", "application/yaml"} {
+			for _, helper := range helpers {
+				request := httptest.NewRequest(http.MethodGet, "/", nil)
+				request.Header.Set("Accept", accept)
+
+				// empty arguments are not cached
+				expected := httptest.NewRecorder()
+				assert.NoError(t, helper(config.Request(expected, request).Language(lang), Args{}))
+
+				for i := 0; i < 2; i++ {
+					recorder := httptest.NewRecorder()
+					assert.NoError(t, helper(config.Request(recorder, request).Language(lang)))
+					assert.Equal(t, expected.Code, recorder.Code)
+					assert.Equal(t, expected.Header(), recorder.Header())
+					assert.Equal(t, expected.Body.String(), recorder.Body.String())
+				}
+			}
+		}
+	}
+	assert.Equal(t, 2*2*len(helpers), staticCount(config.Messages()))
+}
+
+func TestStaticResponsesSettings(t *testing.T) {
+
+	t.Parallel()
+
+	config := New()
+	recorder := httptest.NewRecorder()
+	assert.NoError(t, config.Writer(recorder).NotFound())
+	assert.JSONEq(t, `{"status":"failed","error":5404,"message":"Oops... The requested page not found!"}`, recorder.Body.String())
+
+	// status profiles and envelopes have their own bodies
+	recorder = httptest.NewRecorder()
+	assert.NoError(t, config.Writer(recorder).SetStatusProfile(StandardStatuses).InsertFailed())
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	recorder = httptest.NewRecorder()
+	assert.NoError(t, config.Writer(recorder).InsertFailed())
+	assert.Equal(t, 448, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	assert.NoError(t, config.Writer(recorder).SetEnvelope(guidelineEnvelope).NotFound())
+	assert.JSONEq(t, `{"success":false,"error":{"code":5404,"message":"Oops... The requested page not found!"}}`, recorder.Body.String())
+	assert.Equal(t, 4, staticCount(config.Messages()))
+
+	// the headers of the response are still written
+	recorder = httptest.NewRecorder()
+	assert.NoError(t, config.Writer(recorder).TokenExpired())
+	assert.NotEmpty(t, recorder.Header().Get("WWW-Authenticate"))
+
+	// and nothing is written twice
+	r := config.Writer(httptest.NewRecorder())
+	assert.NoError(t, r.NotFound())
+	assert.ErrorIs(t, r.NotFound(), ErrAlreadyWritten)
+}
+
+func TestStaticResponsesEncoders(t *testing.T) {
+
+	t.Parallel()
+
+	encoder := func(body string) Encoder {
+		return EncoderFunc(func(w io.Writer, v interface{}) error {
+			_, err := io.WriteString(w, body)
+			return err
+		})
+	}
+
+	// configs with their own encoders never share a body
+	config := New(WithEncoder(MediaTypeJSON, encoder("first")))
+	for i, body := range []string{"first", "second", "third"} {
+		if i > 0 {
+			config = config.With(WithEncoder(MediaTypeJSON, encoder(body)))
+		}
+		recorder := httptest.NewRecorder()
+		assert.NoError(t, config.Writer(recorder).NotFound())
+		assert.Equal(t, body, recorder.Body.String())
+	}
+}
+
+func TestStaticResponsesXMLRoot(t *testing.T) {
+	// not parallel, it changes XMLRootElement
+	defer func(root string) { XMLRootElement = root }(XMLRootElement)
+
+	config := New()
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("Accept", MediaTypeXML)
+	for _, root := range []string{"response", "result"} {
+		XMLRootElement = root
+		recorder := httptest.NewRecorder()
+		assert.NoError(t, config.Request(recorder, request).NotFound())
+		assert.Contains(t, recorder.Body.String(), "<"+root+">")
+	}
+}
+
+func TestStaticResponsesInvalidation(t *testing.T) {
+
+	t.Parallel()
+
+	config := New()
+	recorder := httptest.NewRecorder()
+	assert.NoError(t, config.Writer(recorder).NotFound())
+	assert.Equal(t, 1, staticCount(config.Messages()))
+
+	config.Messages().AddLanguageTranslation("en", map[string]interface{}{
+		"success": "ok",
+		"failed":  "error",
+		"errors": map[string]interface{}{
+			"5404": map[string]interface{}{"message": "Nothing here"},
+		},
+	})
+	assert.Equal(t, 0, staticCount(config.Messages()))
+
+	recorder = httptest.NewRecorder()
+	assert.NoError(t, config.Writer(recorder).NotFound())
+	assert.JSONEq(t, `{"status":"error","error":5404,"message":"Nothing here"}`, recorder.Body.String())
+}
+
+func TestStaticResponsesSkipped(t *testing.T) {
+
+	t.Parallel()
+
+	var missing []string
+	config := New(WithHooks(Hooks{OnMissing: func(lang, key string) {
+		missing = append(missing, key)
+	}}))
+
+	// missing translations are reported every time
+	for i := 0; i < 2; i++ {
+		assert.NoError(t, config.Writer(httptest.NewRecorder()).Error(http.StatusBadRequest, 9999))
+	}
+	assert.Equal(t, []string{"errors.9999.message", "errors.9999.message"}, missing)
+
+	// problem documents hold the URL of the request
+	request := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	recorder := httptest.NewRecorder()
+	assert.NoError(t, config.With(WithMode(ModeProblem)).Request(recorder, request).NotFound())
+	assert.Contains(t, recorder.Body.String(), `"instance":"/users/1"`)
+
+	assert.Equal(t, 0, staticCount(config.Messages()))
+}
+
+func BenchmarkNotFound(b *testing.B) {
+	request := httptest.NewRequest(http.MethodGet, "/missing", nil)
+	request.Header.Set("Accept-Language", "fa")
+	config := New()
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			config.Request(httptest.NewRecorder(), request).NotFound()
+		}
+	})
+}
+
+func BenchmarkInsertSucceeded(b *testing.B) {
+	config := New()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		config.Writer(httptest.NewRecorder()).InsertSucceeded()
+	}
+}
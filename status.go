@@ -0,0 +1,73 @@
+package respond
+
+// StatusProfile maps catalogued error codes to the HTTP status of their
+// responses, the codes which are not in the profile keep their status
+type StatusProfile map[int]int
+
+// LegacyStatuses are the original statuses of the catalog, some of them
+// are not registered HTTP statuses. New Respond instances have no
+// profile, which keeps the statuses the errors are sent with and so the
+// legacy ones of the built-in helpers
+var LegacyStatuses = StatusProfile{
+	1001: 446,
+	5420: 420,
+	5447: 447,
+	5448: 448,
+	5449: 449,
+}
+
+// StandardStatuses replace the unregistered statuses of the catalog with
+// registered ones (RFC 9110). The failed writes 5447, 5448 and 5449 are
+// all server errors, an application whose failures are conflicts
+// overrides them with 409
+var StandardStatuses = StatusProfile{
+	1001: 404,
+	5420: 422,
+	5447: 500,
+	5448: 500,
+	5449: 500,
+}
+
+// Get a copy of the profile with the statuses of some codes replaced
+//
+//      r.SetStatusProfile(respond.StandardStatuses.With(respond.StatusProfile{5447: 409, 5448: 409, 5449: 409}))
+//
+// @param overrides StatusProfile
+// @return StatusProfile
+func (p StatusProfile) With(overrides StatusProfile) StatusProfile {
+	profile := make(StatusProfile, len(p)+len(overrides))
+	for code, status := range p {
+		profile[code] = status
+	}
+	for code, status := range overrides {
+		profile[code] = status
+	}
+	return profile
+}
+
+// Set the status profile of the errors of the built-in helpers and of
+// Err, the status given to Error is always kept
+//
+//      r.SetStatusProfile(respond.StandardStatuses).ValidationErrors(v) // 422
+//
+// @param profile StatusProfile
+// @return *Respond
+func (r *Respond) SetStatusProfile(profile StatusProfile) *Respond {
+	r.statuses = profile
+	return r
+}
+
+// Get the status of an error code in the profile of the response, the
+// given status is used for the codes which are not in the profile
+func (r *Respond) statusOf(errorCode, statusCode int) int {
+	if status, ok := r.statuses[errorCode]; ok {
+		return status
+	}
+	return statusCode
+}
+
+// Respond with an error of a built-in helper, the status is the one of
+// the code in the profile of the response
+func (r *Respond) catalogError(statusCode, errorCode int, args ...Args) error {
+	return r.Error(r.statusOf(errorCode, statusCode), errorCode, args...)
+}
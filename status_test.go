@@ -0,0 +1,84 @@
+package respond
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusProfiles(t *testing.T) {
+
+	t.Parallel()
+
+	cases := []struct {
+		send     func(r *Respond) error
+		legacy   int
+		standard int
+	}{
+		{func(r *Respond) error { return r.ValidationErrors(NewValidation().Add("email", "required")) }, 420, http.StatusUnprocessableEntity},
+		{func(r *Respond) error { return r.RequestFieldNotfound() }, 446, http.StatusNotFound},
+		{func(r *Respond) error { return r.DeleteFailed() }, 447, http.StatusInternalServerError},
+		{func(r *Respond) error { return r.InsertFailed() }, 448, http.StatusInternalServerError},
+		{func(r *Respond) error { return r.UpdateFailed() }, 449, http.StatusInternalServerError},
+		{func(r *Respond) error { return r.Err(ErrValidationFailed) }, 420, http.StatusUnprocessableEntity},
+		{func(r *Respond) error { return r.FieldNotFound() }, 446, http.StatusNotFound},
+		{func(r *Respond) error { return r.NotFound() }, http.StatusNotFound, http.StatusNotFound},
+	}
+	for _, c := range cases {
+		recorder := httptest.NewRecorder()
+		assert.NoError(t, c.send(NewWithWriter(recorder)))
+		assert.Equal(t, c.legacy, recorder.Code)
+
+		recorder = httptest.NewRecorder()
+		assert.NoError(t, c.send(NewWithWriter(recorder).SetStatusProfile(LegacyStatuses)))
+		assert.Equal(t, c.legacy, recorder.Code)
+
+		recorder = httptest.NewRecorder()
+		assert.NoError(t, c.send(NewWithWriter(recorder).SetStatusProfile(StandardStatuses)))
+		assert.Equal(t, c.standard, recorder.Code)
+	}
+}
+
+func TestStatusProfileWith(t *testing.T) {
+
+	t.Parallel()
+
+	profile := StandardStatuses.With(StatusProfile{5448: http.StatusConflict})
+	assert.Equal(t, http.StatusConflict, profile[5448])
+	assert.Equal(t, http.StatusInternalServerError, StandardStatuses[5448])
+
+	recorder := httptest.NewRecorder()
+	assert.NoError(t, NewWithWriter(recorder).SetStatusProfile(profile).InsertFailed())
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	assert.NoError(t, NewWithWriter(recorder).SetStatusProfile(profile).SetMode(ModeProblem).ValidationErrors(nil))
+	assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"status":422`)
+}
+
+func TestStatusProfileExplicitStatus(t *testing.T) {
+
+	t.Parallel()
+
+	// the status given to Error is kept whatever the profile is
+	for _, profile := range []StatusProfile{LegacyStatuses, StandardStatuses} {
+		recorder := httptest.NewRecorder()
+		assert.NoError(t, NewWithWriter(recorder).SetStatusProfile(profile).Error(http.StatusBadRequest, 1001))
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	}
+}
+
+func TestStatusProfileMiddleware(t *testing.T) {
+
+	t.Parallel()
+
+	handler := Middleware(Options{Statuses: StandardStatuses})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		From(r).RequestFieldNotfound(Args{"field": "name"})
+	}))
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
@@ -0,0 +1,318 @@
+package respond
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MediaTypeNDJSON is the media type of newline delimited JSON streams
+const MediaTypeNDJSON = "application/x-ndjson"
+
+// Iterator returns the items of a stream one by one, ok is false after
+// the last item and an error ends the stream
+type Iterator func() (item interface{}, ok bool, err error)
+
+// Set how often streamed responses are flushed to the client, the items
+// written since the last flush are flushed on every tick of the interval
+// even while the source is waiting for the next item. Every item is
+// flushed when it is 0
+//
+// @param interval time.Duration
+// @return *Respond
+func (r *Respond) SetFlushInterval(interval time.Duration) *Respond {
+	r.flushInterval = interval
+	return r
+}
+
+// Stream the items of a channel or an Iterator as the result of the
+// envelope, so a large list is never held in memory. An item of a
+// channel which is an error ends the stream like an error of an
+// Iterator. The status is written before the first item, so an error in
+// the middle of the stream is added as an error record in the catalog
+// format after the result and returned
+//
+//      rows := make(chan interface{})
+//      go export(rows)
+//      return r.Stream(req.Context(), rows)
+//      // {"status":"success","result":[...],"error":{"status":"failed","error":5500,"message":"..."}}
+//
+// @param ctx context.Context, source interface{}
+// @return error
+func (r *Respond) Stream(ctx context.Context, source interface{}) error {
+	return r.stream(ctx, source, false)
+}
+
+// Stream the items of a channel or an Iterator as newline delimited JSON,
+// one item per line. An error in the middle of the stream is written as
+// a last line in the catalog format and returned
+//
+// @param ctx context.Context, source interface{}
+// @return error
+func (r *Respond) StreamNDJSON(ctx context.Context, source interface{}) error {
+	return r.stream(ctx, source, true)
+}
+
+func (r *Respond) stream(ctx context.Context, source interface{}, ndjson bool) error {
+	if r.written {
+		return ErrAlreadyWritten
+	}
+	next, err := iterate(ctx, source)
+	if err != nil {
+		return err
+	}
+	r.written = true
+	r.SetStatusCode(http.StatusOK).SetStatusText(r.successText())
+
+	header := r.writer.Header()
+	header.Del("Content-Length")
+	if ndjson {
+		header.Set("Content-Type", MediaTypeNDJSON)
+	} else {
+		header.Set("Content-Type", contentType(MediaTypeJSON))
+	}
+	r.writer.WriteHeader(r.statusCode)
+
+	s := &streamWriter{w: r.writer, interval: r.flushInterval}
+	s.flusher, _ = r.writer.(http.Flusher)
+	s.start()
+	defer s.stop()
+	if !ndjson {
+		s.prefix, s.suffix = r.streamEnvelope()
+		s.write([]byte(s.prefix + "["))
+	}
+
+	for s.err == nil {
+		item, ok, err := next()
+		if err != nil {
+			return r.streamError(s, ndjson, err)
+		}
+		if !ok {
+			break
+		}
+		b, err := json.Marshal(item)
+		if err != nil {
+			return r.streamError(s, ndjson, &EncodeError{MediaType: MediaTypeJSON, Err: err})
+		}
+		if ndjson {
+			b = append(b, '\n')
+		} else if s.count > 0 {
+			s.write([]byte{','})
+		}
+		s.write(b)
+		s.flush(false)
+		s.count++
+	}
+	if !ndjson {
+		s.write([]byte("]" + s.suffix))
+	}
+	s.flush(true)
+	r.responded(r.statusCode)
+	return s.err
+}
+
+// End a stream with an error record, a *Error in the chain of the error
+// is written with its code and the others as an internal server error
+func (r *Respond) streamError(s *streamWriter, ndjson bool, err error) error {
+	e := ErrInternalServerError
+	var catalogued *Error
+	if errors.As(err, &catalogued) {
+		e = catalogued
+	}
+	message, _ := r.translate(e.Key, nil)
+	data := EnvelopeData{
+		Status:     r.failedText(),
+		Code:       e.Code,
+		Message:    message,
+		HasMessage: true,
+	}
+	if ndjson {
+		record, _ := json.Marshal(r.envelope.Build(data))
+		s.write(append(record, '\n'))
+		s.flush(true)
+		r.responded(r.statusCode)
+		return err
+	}
+	key, value := r.envelope.errorRecord(data)
+	record, _ := json.Marshal(value)
+	switch {
+	case strings.HasPrefix(s.suffix, "}"):
+		name, _ := json.Marshal(key)
+		s.write([]byte("]," + string(name) + ":" + string(record) + s.suffix))
+	case s.count > 0:
+		s.write([]byte("," + string(record) + "]" + s.suffix))
+	default:
+		s.write([]byte(string(record) + "]" + s.suffix))
+	}
+	s.flush(true)
+	r.responded(r.statusCode)
+	return err
+}
+
+// The marker of the result in the envelope of streams
+const streamMarker = "respond:stream:result"
+
+// Split the envelope of a stream around its result. The result is the
+// last member of an envelope object, so an error record can follow it,
+// and a bare array when the envelope has no result
+func (r *Respond) streamEnvelope() (string, string) {
+	data := r.envelopeData()
+	data.Result, data.HasResult = streamMarker, true
+	envelope := r.envelope.Build(data)
+	if body, ok := envelope.(map[string]interface{}); ok {
+		for key, value := range body {
+			if value != streamMarker {
+				continue
+			}
+			delete(body, key)
+			rest, err := json.Marshal(body)
+			if err != nil {
+				return "", ""
+			}
+			name, _ := json.Marshal(key)
+			if len(body) == 0 {
+				return "{" + string(name) + ":", "}"
+			}
+			return string(rest[:len(rest)-1]) + "," + string(name) + ":", "}"
+		}
+	}
+	body, err := json.Marshal(envelope)
+	if err != nil {
+		return "", ""
+	}
+	marker, _ := json.Marshal(streamMarker)
+	i := bytes.Index(body, marker)
+	if i < 0 {
+		return "", ""
+	}
+	return string(body[:i]), string(body[i+len(marker):])
+}
+
+// Get the iterator of a stream source, which is an Iterator or a channel
+func iterate(ctx context.Context, source interface{}) (Iterator, error) {
+	switch it := source.(type) {
+	case Iterator:
+		return contextIterator(ctx, it), nil
+	case func() (interface{}, bool, error):
+		return contextIterator(ctx, it), nil
+	}
+	ch := reflect.ValueOf(source)
+	if ch.Kind() != reflect.Chan || ch.Type().ChanDir()&reflect.RecvDir == 0 {
+		return nil, fmt.Errorf("respond: can not stream %T, a channel or an Iterator is required", source)
+	}
+	cases := []reflect.SelectCase{
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())},
+		{Dir: reflect.SelectRecv, Chan: ch},
+	}
+	return func() (interface{}, bool, error) {
+		chosen, value, ok := reflect.Select(cases)
+		if chosen == 0 {
+			return nil, false, ctx.Err()
+		}
+		if !ok {
+			return nil, false, nil
+		}
+		item := value.Interface()
+		if err, isErr := item.(error); isErr {
+			return nil, false, err
+		}
+		return item, true, nil
+	}, nil
+}
+
+func contextIterator(ctx context.Context, next Iterator) Iterator {
+	return func() (interface{}, bool, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, false, err
+		}
+		return next()
+	}
+}
+
+// Writes a stream and flushes it to the client, the first error of the
+// writer stops the stream. With an interval a ticker flushes the stream,
+// the writer is locked so it is never flushed while it is written
+type streamWriter struct {
+	w        http.ResponseWriter
+	flusher  http.Flusher
+	interval time.Duration
+	err      error
+
+	// Whether there are written bytes which are not flushed yet
+	pending bool
+	done    chan struct{}
+	stopped chan struct{}
+	mu      sync.Mutex
+
+	// Envelope around the items and the number of written items
+	prefix string
+	suffix string
+	count  int
+}
+
+// Start flushing the stream on every tick of the interval
+func (s *streamWriter) start() {
+	if s.interval <= 0 || s.flusher == nil {
+		return
+	}
+	s.done, s.stopped = make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(s.stopped)
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.mu.Lock()
+				if s.pending && s.err == nil {
+					s.flusher.Flush()
+					s.pending = false
+				}
+				s.mu.Unlock()
+			case <-s.done:
+				return
+			}
+		}
+	}()
+}
+
+// Stop the ticker, the writer is not flushed by it once this returns
+func (s *streamWriter) stop() {
+	if s.done == nil {
+		return
+	}
+	close(s.done)
+	<-s.stopped
+}
+
+func (s *streamWriter) write(b []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return
+	}
+	_, s.err = s.w.Write(b)
+	s.pending = true
+}
+
+// Flush the written bytes, with an interval they are left to the ticker
+// unless the flush is forced
+func (s *streamWriter) flush(force bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil || s.flusher == nil {
+		return
+	}
+	if !force && s.interval > 0 {
+		return
+	}
+	s.flusher.Flush()
+	s.pending = false
+}
@@ -0,0 +1,174 @@
+package respond
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamChannel(t *testing.T) {
+
+	t.Parallel()
+
+	items := make(chan map[string]int)
+	go func() {
+		for i := 1; i <= 3; i++ {
+			items <- map[string]int{"id": i}
+		}
+		close(items)
+	}()
+
+	recorder := httptest.NewRecorder()
+	assert.NoError(t, NewWithWriter(recorder).Stream(context.Background(), items))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.True(t, recorder.Flushed)
+	assert.Equal(t, "application/json; charset=utf-8", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, `{"status":"success","result":[{"id":1},{"id":2},{"id":3}]}`, recorder.Body.String())
+}
+
+func TestStreamIterator(t *testing.T) {
+
+	t.Parallel()
+
+	i := 0
+	next := func() (interface{}, bool, error) {
+		i++
+		return i, i <= 2, nil
+	}
+
+	recorder := httptest.NewRecorder()
+	assert.NoError(t, NewWithWriter(recorder).Language("fa").Stream(context.Background(), Iterator(next)))
+	assert.Equal(t, `{"status":"موفق","result":[1,2]}`, recorder.Body.String())
+
+	recorder = httptest.NewRecorder()
+	assert.NoError(t, NewWithWriter(recorder).Stream(context.Background(), func() (interface{}, bool, error) {
+		return nil, false, nil
+	}))
+	assert.Equal(t, `{"status":"success","result":[]}`, recorder.Body.String())
+}
+
+func TestStreamError(t *testing.T) {
+
+	t.Parallel()
+
+	items := make(chan interface{}, 3)
+	items <- "a"
+	items <- fmt.Errorf("export: %w", ErrDatabaseConnectionRefused)
+	items <- "b"
+
+	recorder := httptest.NewRecorder()
+	err := NewWithWriter(recorder).Stream(context.Background(), items)
+
+	assert.True(t, errors.Is(err, ErrDatabaseConnectionRefused))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `{
+		"status": "success",
+		"result": ["a"],
+		"error": {"status": "failed", "error": 5445, "message": "Oops... Database connection refused"}
+	}`, recorder.Body.String())
+}
+
+func TestStreamEncodeError(t *testing.T) {
+
+	t.Parallel()
+
+	items := make(chan interface{}, 2)
+	items <- 1
+	items <- func() {}
+	close(items)
+
+	recorder := httptest.NewRecorder()
+	err := NewWithWriter(recorder).StreamNDJSON(context.Background(), items)
+
+	assert.True(t, errors.Is(err, ErrEncode))
+	assert.Equal(t, "1\n"+`{"error":5500,"message":"Oops... Something went wrong on our side!","status":"failed"}`+"\n", recorder.Body.String())
+}
+
+func TestStreamNDJSON(t *testing.T) {
+
+	t.Parallel()
+
+	items := make(chan string, 2)
+	items <- "a"
+	items <- "b"
+	close(items)
+
+	recorder := httptest.NewRecorder()
+	assert.NoError(t, NewWithWriter(recorder).SetFlushInterval(time.Hour).StreamNDJSON(context.Background(), items))
+
+	assert.Equal(t, MediaTypeNDJSON, recorder.Header().Get("Content-Type"))
+	assert.Equal(t, "\"a\"\n\"b\"\n", recorder.Body.String())
+}
+
+// A recorder which counts its flushes
+type flushCounter struct {
+	*httptest.ResponseRecorder
+	flushes int32
+}
+
+func (f *flushCounter) Flush() {
+	atomic.AddInt32(&f.flushes, 1)
+	f.ResponseRecorder.Flush()
+}
+
+func TestStreamFlushInterval(t *testing.T) {
+
+	t.Parallel()
+
+	items := make(chan int)
+	recorder := &flushCounter{ResponseRecorder: httptest.NewRecorder()}
+	done := make(chan error)
+	go func() {
+		done <- NewWithWriter(recorder).SetFlushInterval(10 * time.Millisecond).StreamNDJSON(context.Background(), items)
+	}()
+
+	// the item is flushed by the ticker while the source waits
+	items <- 1
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&recorder.flushes) == 1 }, time.Second, 5*time.Millisecond)
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&recorder.flushes))
+
+	close(items)
+	assert.NoError(t, <-done)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&recorder.flushes))
+	assert.Equal(t, "1\n", recorder.Body.String())
+}
+
+func TestStreamCanceled(t *testing.T) {
+
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	items := make(chan int)
+	go func() {
+		items <- 1
+		cancel()
+	}()
+
+	recorder := httptest.NewRecorder()
+	err := NewWithWriter(recorder).Stream(ctx, items)
+
+	assert.Equal(t, context.Canceled, err)
+	assert.Contains(t, recorder.Body.String(), `{"status":"success","result":[1],"error":{`)
+}
+
+func TestStreamInvalidSource(t *testing.T) {
+
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+	r := NewWithWriter(recorder)
+	assert.EqualError(t, r.Stream(context.Background(), []int{1}), "respond: can not stream []int, a channel or an Iterator is required")
+	assert.False(t, r.Written())
+
+	assert.NoError(t, r.Succeed(nil))
+	assert.Equal(t, ErrAlreadyWritten, r.Stream(context.Background(), make(chan int)))
+}
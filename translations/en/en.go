@@ -17,24 +17,29 @@ var Messages = map[string]interface{}{
 			"update": "The requested parameter is not updated!",
 		},
 		"1001": {
-			"message": "Oops... Requested field is not found!",
+			"message": "Oops... Requested field {field} is not found!",
 			"type":    "error",
+			"short":   "field-not-found",
 		},
 		"1002": {
 			"message": "Oops... Requested User does not exists!",
 			"type":    "error",
+			"short":   "user-not-found",
 		},
 		"1003": {
 			"message": "Oops... Client type is not entered!",
 			"type":    "error",
+			"short":   "client-type-missing",
 		},
 		"1004": {
-			"message": "Failed because of duplicate",
+			"message": "Failed because of duplicate {field}",
 			"type":    "error",
+			"short":   "duplicated",
 		},
 		"1005": {
 			"message": "Failed because of dablicated user role",
 			"type":    "error",
+			"short":   "duplicated-user-role",
 		},
 		"3001": {
 			"message": "You are not logged on",
@@ -46,106 +51,156 @@ var Messages = map[string]interface{}{
 			"message": "Application token did not generated successfully",
 			"type":    "error",
 			"cat":     "auth",
+			"short":   "app-token-not-generated",
 		},
 		"3003": {
 			"message": "User token did not generated successfully",
 			"type":    "error",
 			"cat":     "auth",
+			"short":   "user-token-not-generated",
 		},
 		"3005": {
 			"message": "Request token did not contains user information",
 			"type":    "error",
 			"cat":     "auth",
+			"short":   "token-without-user",
 		},
 		"3006": {
 			"message": "Did not set request token",
 			"type":    "error",
 			"cat":     "auth",
+			"short":   "token-not-set",
 		},
 		"3007": {
 			"message": "can not decode the token",
 			"type":    "error",
 			"cat":     "auth",
+			"short":   "token-decode-failed",
 		},
 		"3008": {
 			"message": "can not generate token for authentication",
 			"type":    "error",
 			"cat":     "auth",
+			"short":   "auth-token-not-generated",
 		},
 		"3009": {
 			"message": "can not create token",
 			"type":    "error",
 			"cat":     "auth",
+			"short":   "token-not-created",
 		},
 		"3010": {
 			"message": "Token expired!",
 			"type":    "error",
 			"cat":     "auth",
+			"short":   "token-expired",
 		},
 		"3011": {
 			"message": "Token is invalid!",
 			"type":    "error",
 			"cat":     "auth",
+			"short":   "token-invalid",
 		},
 		"3012": {
 			"message": "Token Blacklisted",
 			"type":    "error",
 			"cat":     "auth",
+			"short":   "token-blacklisted",
 		},
 		"3013": {
 			"message": "Payload invalid!",
 			"type":    "error",
 			"cat":     "auth",
+			"short":   "payload-invalid",
 		},
 		"3014": {
 			"message": "Claim Invalid",
 			"type":    "error",
 			"cat":     "auth",
+			"short":   "claim-invalid",
 		},
 		"3015": {
 			"message": "An error occurred on token validation",
 			"type":    "error",
 			"cat":     "auth",
+			"short":   "token-validation-failed",
 		},
 		"5401": {
 			"message": "Authentication unauthorized...",
 			"type":    "error",
+			"short":   "unauthorized",
+		},
+		"5403": {
+			"message": "You do not have permission to access the requested resource",
+			"type":    "error",
+			"short":   "forbidden",
 		},
 		"5404": {
 			"message": "Oops... The requested page not found!",
 			"type":    "error",
+			"short":   "not-found",
 		},
 		"5405": {
 			"message": "Oops... The method you requested is not allowed!",
 			"type":    "error",
+			"short":   "method-not-allowed",
 		},
 		"5406": {
 			"message": "Oops... The parameters you entered are wrong!",
 			"type":    "error",
+			"short":   "wrong-parameters",
+		},
+		"5412": {
+			"message": "Oops... The requested resource is changed by another request!",
+			"type":    "error",
+			"short":   "precondition-failed",
 		},
 		"5420": {
 			"message": "Validation Error",
 			"type":    "error",
+			"short":   "validation-failed",
 		},
 		"5422": {
 			"message": "Token is not valid",
 			"type":    "error",
+			"short":   "token-not-valid",
 		},
 		"5445": {
 			"message": "Oops... Database connection refused",
 			"type":    "error",
+			"short":   "database-connection-refused",
 		},
 		"5447": {
 			"message": "Oops... Delete action was not successfully executed",
 			"type":    "error",
+			"short":   "delete-failed",
 		},
 		"5448": {
 			"message": "Oops... Insert action was not successfully executed",
 			"type":    "error",
+			"short":   "insert-failed",
 		},
 		"5449": {
 			"message": "Oops... Update action was not successfully executed",
 			"type":    "error",
+			"short":   "update-failed",
 		},
+		"5500": {
+			"message": "Oops... Something went wrong on our side!",
+			"type":    "error",
+			"short":   "internal-server-error",
+		},
+	},
+	"validation": map[string]interface{}{
+		"invalid":  "The {field} field is invalid",
+		"required": "The {field} field is required",
+		"email":    "The {field} field must be a valid email address",
+		"url":      "The {field} field must be a valid URL",
+		"numeric":  "The {field} field must be a number",
+		"min":      "The {field} field must be at least {min}",
+		"max":      "The {field} field may not be greater than {max}",
+		"len":      "The {field} field must be {len} long",
+		"oneof":    "The {field} field must be one of {oneof}",
+		"unique":   "The {field} has already been taken",
 	},
 }
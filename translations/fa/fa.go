@@ -17,24 +17,29 @@ var Messages = map[string]interface{}{
 			"update": ".درخواست با موفقیت ویرایش  نشد",
 		},
 		"1001": {
-			"message": ".درخواست مورد نظر پیدا نشده است",
+			"message": ".فیلد {field} درخواست شده پیدا نشده است",
 			"type":    "error",
+			"short":   "field-not-found",
 		},
 		"1002": {
 			"message": ".کاربر مورد نظر موجود نیست",
 			"type":    "error",
+			"short":   "user-not-found",
 		},
 		"1003": {
 			"message": ".نوع کاربری وارد نشده است",
 			"type":    "error",
+			"short":   "client-type-missing",
 		},
 		"1004": {
-			"message": ".ورودی مورد نظر تکراری است",
+			"message": ".ورودی {field} مورد نظر تکراری است",
 			"type":    "error",
+			"short":   "duplicated",
 		},
 		"1005": {
 			"message": ".نقش کاربر مورد نظر تکراری است",
 			"type":    "error",
+			"short":   "duplicated-user-role",
 		},
 		"3001": {
 			"message": ".شما به سیستم وارد نشده اید",
@@ -43,109 +48,159 @@ var Messages = map[string]interface{}{
 			"short":   "not-logged-on",
 		},
 		"3002": {
-			"message": ".نشان شناسایی شما نامعتبر است",
+			"message": ".نشان شناسایی برنامه با موفقیت ساخته نشد",
 			"type":    "error",
 			"cat":     "auth",
+			"short":   "app-token-not-generated",
 		},
 		"3003": {
-			"message": ".نشان شناسایی شما نامعتبر است",
+			"message": ".نشان شناسایی کاربر با موفقیت ساخته نشد",
 			"type":    "error",
 			"cat":     "auth",
+			"short":   "user-token-not-generated",
 		},
 		"3005": {
-			"message": ".نشان شناسایی شما نامعتبر است",
+			"message": ".نشان شناسایی درخواست اطلاعات کاربر را ندارد",
 			"type":    "error",
 			"cat":     "auth",
+			"short":   "token-without-user",
 		},
 		"3006": {
-			"message": ".نشان شناسایی شما نامعتبر است",
+			"message": ".نشان شناسایی درخواست ارسال نشده است",
 			"type":    "error",
 			"cat":     "auth",
+			"short":   "token-not-set",
 		},
 		"3007": {
 			"message": ".نشان شناسایی شما نمایش داده نمیشود",
 			"type":    "error",
 			"cat":     "auth",
+			"short":   "token-decode-failed",
 		},
 		"3008": {
-			"message": ".نشان شناسایی شما نامعتبر است",
+			"message": ".ساخت نشان شناسایی برای احراز هویت ممکن نیست",
 			"type":    "error",
 			"cat":     "auth",
+			"short":   "auth-token-not-generated",
 		},
 		"3009": {
-			"message": ".نشان شناسایی شما نامعتبر است",
+			"message": ".ایجاد نشان شناسایی ممکن نیست",
 			"type":    "error",
 			"cat":     "auth",
+			"short":   "token-not-created",
 		},
 		"3010": {
 			"message": ".زمان استفاده از نشان شناسایی شما گذشته است",
 			"type":    "error",
 			"cat":     "auth",
+			"short":   "token-expired",
 		},
 		"3011": {
 			"message": ".نشان شناسایی شما نامعتبر است",
 			"type":    "error",
 			"cat":     "auth",
+			"short":   "token-invalid",
 		},
 		"3012": {
-			"message": ".نشان شناسایی شما نامعتبر است",
+			"message": ".نشان شناسایی شما مسدود شده است",
 			"type":    "error",
 			"cat":     "auth",
+			"short":   "token-blacklisted",
 		},
 		"3013": {
 			"message": ".Payload معتبر نیست",
 			"type":    "error",
 			"cat":     "auth",
+			"short":   "payload-invalid",
 		},
 		"3014": {
 			"message": ".Claim معتبر نیست",
 			"type":    "error",
 			"cat":     "auth",
+			"short":   "claim-invalid",
 		},
 		"3015": {
-			"message": ".نشان شناسایی شما نامعتبر است",
+			"message": ".در اعتبار سنجی نشان شناسایی خطایی رخ داده است",
 			"type":    "error",
 			"cat":     "auth",
+			"short":   "token-validation-failed",
 		},
 		"5401": {
 			"message": ".شناسایی کاربر نامعتبر است",
 			"type":    "error",
+			"short":   "unauthorized",
+		},
+		"5403": {
+			"message": ".شما دسترسی به منبع درخواست شده را ندارید",
+			"type":    "error",
+			"short":   "forbidden",
 		},
 		"5404": {
 			"message": ".صفحه درخواست شده پیدا نمیشود",
 			"type":    "error",
+			"short":   "not-found",
 		},
 		"5405": {
 			"message": ".شما به درخواستی که داده اید دسترسی ندارید",
 			"type":    "error",
+			"short":   "method-not-allowed",
 		},
 		"5406": {
 			"message": ".پارامترهایی که شما وارد کرده اید نا معتبر است",
 			"type":    "error",
+			"short":   "wrong-parameters",
+		},
+		"5412": {
+			"message": ".منبع درخواست شده توسط درخواست دیگری تغییر کرده است",
+			"type":    "error",
+			"short":   "precondition-failed",
 		},
 		"5420": {
 			"message": ".خطای اعتبار سنجی",
 			"type":    "error",
+			"short":   "validation-failed",
 		},
 		"5422": {
-			"message": ".نشان شناسایی شما نامعتبر است",
+			"message": ".نشان شناسایی معتبر نیست",
 			"type":    "error",
+			"short":   "token-not-valid",
 		},
 		"5445": {
 			"message": ".ارتباط با پایگاه داده مشکل دارد",
 			"type":    "error",
+			"short":   "database-connection-refused",
 		},
 		"5447": {
 			"message": ".عملیات پاک کردن درست اجرا نشده است",
 			"type":    "error",
+			"short":   "delete-failed",
 		},
 		"5448": {
 			"message": ".عملیات درج درست اجرا نشده است",
 			"type":    "error",
+			"short":   "insert-failed",
 		},
 		"5449": {
 			"message": ".عملیات ویرایش درست اجرا نشده است",
 			"type":    "error",
+			"short":   "update-failed",
+		},
+		"5500": {
+			"message": ".خطایی در سرور رخ داده است",
+			"type":    "error",
+			"short":   "internal-server-error",
 		},
 	},
+	"validation": map[string]interface{}{
+		"invalid":  "فیلد {field} معتبر نیست",
+		"required": "فیلد {field} الزامی است",
+		"email":    "فیلد {field} باید یک ایمیل معتبر باشد",
+		"url":      "فیلد {field} باید یک آدرس معتبر باشد",
+		"numeric":  "فیلد {field} باید عدد باشد",
+		"min":      "فیلد {field} باید حداقل {min} باشد",
+		"max":      "فیلد {field} نباید بیشتر از {max} باشد",
+		"len":      "طول فیلد {field} باید {len} باشد",
+		"oneof":    "فیلد {field} باید یکی از {oneof} باشد",
+		"unique":   "{field} قبلا ثبت شده است",
+	},
 }
@@ -0,0 +1,291 @@
+package respond
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// FieldError is an error of a field of the request, it is encoded as
+//
+//      {"field":"/user/age","rule":"min","params":{"min":18},"message":"The age field must be at least 18"}
+type FieldError struct {
+	// Field is the JSON pointer (RFC 6901) of the field
+	Field string `json:"field"`
+
+	// Rule is the name of the failed rule, like required or min
+	Rule string `json:"rule"`
+
+	// Params of the rule, they are the arguments of the message with the
+	// name of the field as {field}
+	Params Args `json:"params,omitempty"`
+
+	// Message is localised from Key when it is empty
+	Message string `json:"message"`
+
+	// Key of the message in the catalog, validation.<rule> is used when
+	// it is empty and validation.invalid when the rule has no message
+	Key string `json:"-"`
+}
+
+// ValidationAdapter converts the errors of a validator library to field
+// errors, ok is false for errors it does not know
+type ValidationAdapter interface {
+	FieldErrors(err error) (fields []*FieldError, ok bool)
+}
+
+// ValidationAdapterFunc is an adapter to use ordinary functions as
+// validation adapters
+type ValidationAdapterFunc func(err error) ([]*FieldError, bool)
+
+func (f ValidationAdapterFunc) FieldErrors(err error) ([]*FieldError, bool) {
+	return f(err)
+}
+
+var validationAdapters = struct {
+	list []ValidationAdapter
+	sync.RWMutex
+}{}
+
+func init() {
+	RegisterValidationAdapter(ValidationAdapterFunc(fieldErrorsOf))
+	RegisterValidationAdapter(ValidationAdapterFunc(playgroundErrors))
+}
+
+// Register an adapter for the errors of a validator library, the adapters
+// registered later are tried first
+//
+//      respond.RegisterValidationAdapter(respond.ValidationAdapterFunc(func(err error) ([]*respond.FieldError, bool) {
+//        var errs ozzo.Errors
+//        if !errors.As(err, &errs) {
+//          return nil, false
+//        }
+//        ...
+//      }))
+//
+// @param adapter ValidationAdapter
+func RegisterValidationAdapter(adapter ValidationAdapter) {
+	validationAdapters.Lock()
+	defer validationAdapters.Unlock()
+	validationAdapters.list = append(validationAdapters.list, adapter)
+}
+
+// Validation collects the field errors of a request
+//
+//      v := respond.NewValidation()
+//      if user.Email == "" {
+//        v.Add("email", "required")
+//      }
+//      if user.Age < 18 {
+//        v.Add("age", "min", respond.Args{"min": 18})
+//      }
+//      if !v.Empty() {
+//        return r.ValidationErrors(v)
+//      }
+type Validation struct {
+	errors []*FieldError
+}
+
+// Create a new validation
+//
+// @return *Validation
+func NewValidation() *Validation {
+	return &Validation{}
+}
+
+// Add an error of a field, the field is a JSON pointer or a path like
+// user.emails[0]
+//
+// @param field string, rule string, params ...Args
+// @return *Validation
+func (v *Validation) Add(field, rule string, params ...Args) *Validation {
+	v.errors = append(v.errors, &FieldError{
+		Field:  FieldPointer(field),
+		Rule:   rule,
+		Params: mergeArgs(params),
+	})
+	return v
+}
+
+// Add field errors as they are
+//
+// @param fields ...*FieldError
+// @return *Validation
+func (v *Validation) Append(fields ...*FieldError) *Validation {
+	v.errors = append(v.errors, fields...)
+	return v
+}
+
+// Add the field errors of a validator library through the registered
+// adapters, it reports whether an adapter knew the error
+//
+//      if err := validate.Struct(user); err != nil && !v.AddError(err) {
+//        return err
+//      }
+//
+// @param err error
+// @return bool
+func (v *Validation) AddError(err error) bool {
+	if err == nil {
+		return false
+	}
+	validationAdapters.RLock()
+	defer validationAdapters.RUnlock()
+	for i := len(validationAdapters.list) - 1; i >= 0; i-- {
+		if fields, ok := validationAdapters.list[i].FieldErrors(err); ok {
+			v.Append(fields...)
+			return true
+		}
+	}
+	return false
+}
+
+// Whether there is no field error
+//
+// @return bool
+func (v *Validation) Empty() bool {
+	return len(v.errors) == 0
+}
+
+// Get the field errors
+//
+// @return []*FieldError
+func (v *Validation) Errors() []*FieldError {
+	return v.errors
+}
+
+// Get the JSON pointer of a field path like user.emails[0], a path which
+// already is a JSON pointer is kept
+//
+//      respond.FieldPointer("user.emails[0]") // /user/emails/0
+//
+// @param path string
+// @return string
+func FieldPointer(path string) string {
+	if path == "" || strings.HasPrefix(path, "/") {
+		return path
+	}
+	path = strings.NewReplacer("[", ".", "]", "").Replace(path)
+	escape := strings.NewReplacer("~", "~0", "/", "~1")
+	var pointer strings.Builder
+	for _, segment := range strings.Split(path, ".") {
+		if segment != "" {
+			pointer.WriteString("/" + escape.Replace(segment))
+		}
+	}
+	return pointer.String()
+}
+
+// Name of the field of a JSON pointer, which is its last segment
+func fieldName(pointer string) string {
+	name := pointer[strings.LastIndex(pointer, "/")+1:]
+	return strings.NewReplacer("~1", "/", "~0", "~").Replace(name)
+}
+
+// Get the field errors of the errors passed to ValidationErrors with
+// localised messages, other values are kept as they are
+func (r *Respond) fieldErrors(errs interface{}) interface{} {
+	var fields []*FieldError
+	switch e := errs.(type) {
+	case *Validation:
+		fields = e.Errors()
+	case []*FieldError:
+		fields = e
+	case error:
+		v := NewValidation()
+		if !v.AddError(e) {
+			return errs
+		}
+		fields = v.Errors()
+	default:
+		return errs
+	}
+
+	localised := make([]*FieldError, 0, len(fields))
+	for _, field := range fields {
+		f := *field
+		if f.Message == "" {
+			f.Message = r.fieldMessage(&f)
+		}
+		localised = append(localised, &f)
+	}
+	return localised
+}
+
+// Localise the message of a field error
+func (r *Respond) fieldMessage(f *FieldError) string {
+	args := Args{"field": fieldName(f.Field)}
+	for k, v := range f.Params {
+		args[k] = v
+	}
+	keys := []string{f.Key, "validation." + f.Rule, "validation.invalid"}
+	for _, key := range keys {
+		if key == "" || key == "validation." {
+			continue
+		}
+		if message, err := r.catalog().translate(r.language(), key, args, nil); err == nil && message != key {
+			return message
+		}
+	}
+	return f.Rule
+}
+
+// The errors which list their field errors themselves
+func fieldErrorsOf(err error) ([]*FieldError, bool) {
+	var e interface{ FieldErrors() []*FieldError }
+	if !errors.As(err, &e) {
+		return nil, false
+	}
+	return e.FieldErrors(), true
+}
+
+// Get the JSON name of a struct field, which is the name of its json tag.
+// Registered as the tag name func of github.com/go-playground/validator,
+// the fields of its errors are the JSON names instead of the Go names
+//
+//      validate := validator.New()
+//      validate.RegisterTagNameFunc(respond.JSONTagName)
+//
+// @param field reflect.StructField
+// @return string
+func JSONTagName(field reflect.StructField) string {
+	name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+// The errors of github.com/go-playground/validator, which are a slice of
+// errors with a namespace, a tag and a param. The first segment of the
+// namespace is the name of the validated struct, the other segments are
+// the names of RegisterTagNameFunc, like JSONTagName
+func playgroundErrors(err error) ([]*FieldError, bool) {
+	type playgroundError interface {
+		Namespace() string
+		Tag() string
+		Param() string
+	}
+	list := reflect.ValueOf(err)
+	if list.Kind() != reflect.Slice || list.Len() == 0 {
+		return nil, false
+	}
+	fields := make([]*FieldError, 0, list.Len())
+	for i := 0; i < list.Len(); i++ {
+		e, ok := list.Index(i).Interface().(playgroundError)
+		if !ok {
+			return nil, false
+		}
+		namespace := e.Namespace()
+		if i := strings.Index(namespace, "."); i >= 0 {
+			namespace = namespace[i+1:]
+		}
+		field := &FieldError{Field: FieldPointer(namespace), Rule: e.Tag()}
+		if param := e.Param(); param != "" {
+			field.Params = Args{e.Tag(): param}
+		}
+		fields = append(fields, field)
+	}
+	return fields, true
+}
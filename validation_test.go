@@ -0,0 +1,149 @@
+package respond
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// An error of a validator library like github.com/go-playground/validator
+type libraryError struct {
+	namespace, tag, param string
+}
+
+func (e libraryError) Namespace() string { return e.namespace }
+func (e libraryError) Tag() string       { return e.tag }
+func (e libraryError) Param() string     { return e.param }
+func (e libraryError) Error() string     { return e.namespace + ": " + e.tag }
+
+type libraryErrors []libraryError
+
+func (e libraryErrors) Error() string { return fmt.Sprint([]libraryError(e)) }
+
+func TestJSONTagName(t *testing.T) {
+
+	t.Parallel()
+
+	type user struct {
+		Email    string `json:"email,omitempty"`
+		Name     string `json:",omitempty"`
+		Password string `json:"-"`
+		Age      int
+	}
+	typ := reflect.TypeOf(user{})
+	var names []string
+	for i := 0; i < typ.NumField(); i++ {
+		names = append(names, JSONTagName(typ.Field(i)))
+	}
+	// the fields without a JSON name keep their Go names in the validator
+	assert.Equal(t, []string{"email", "", "", ""}, names)
+}
+
+func TestFieldPointer(t *testing.T) {
+
+	t.Parallel()
+
+	assert.Equal(t, "/email", FieldPointer("email"))
+	assert.Equal(t, "/user/emails/0", FieldPointer("user.emails[0]"))
+	assert.Equal(t, "/a~1b/c~0d", FieldPointer("a/b.c~d"))
+	assert.Equal(t, "/user/name", FieldPointer("/user/name"))
+	assert.Equal(t, "", FieldPointer(""))
+}
+
+func TestValidationFieldErrors(t *testing.T) {
+
+	t.Parallel()
+
+	v := NewValidation().
+		Add("email", "required").
+		Add("user.age", "min", Args{"min": 18}).
+		Add("nickname", "custom")
+	assert.False(t, v.Empty())
+
+	recorder := httptest.NewRecorder()
+	assert.NoError(t, NewWithWriter(recorder).ValidationErrors(v))
+	assert.Equal(t, 420, recorder.Code)
+	assert.JSONEq(t, `{
+		"status": "failed",
+		"error": 5420,
+		"message": "Validation Error",
+		"result": [
+			{"field": "/email", "rule": "required", "message": "The email field is required"},
+			{"field": "/user/age", "rule": "min", "params": {"min": 18}, "message": "The age field must be at least 18"},
+			{"field": "/nickname", "rule": "custom", "message": "The nickname field is invalid"}
+		]
+	}`, recorder.Body.String())
+
+	// the messages of the builder are not changed
+	assert.Empty(t, v.Errors()[0].Message)
+
+	recorder = httptest.NewRecorder()
+	assert.NoError(t, NewWithWriter(recorder).Language("fa").ValidationErrors(NewValidation().Add("email", "required")))
+	assert.Contains(t, recorder.Body.String(), `"message":"فیلد email الزامی است"`)
+}
+
+func TestValidationErrorsMessages(t *testing.T) {
+
+	t.Parallel()
+
+	fields := []*FieldError{
+		{Field: "/code", Rule: "format", Message: "Use 6 digits"},
+		{Field: "/email", Rule: "taken", Key: "validation.unique"},
+	}
+	recorder := httptest.NewRecorder()
+	assert.NoError(t, NewWithWriter(recorder).ValidationErrors(fields))
+	assert.Contains(t, recorder.Body.String(), `"message":"Use 6 digits"`)
+	assert.Contains(t, recorder.Body.String(), `"message":"The email has already been taken"`)
+
+	// other values are sent as they are
+	recorder = httptest.NewRecorder()
+	assert.NoError(t, NewWithWriter(recorder).ValidationErrors(map[string]string{"email": "required"}))
+	assert.Contains(t, recorder.Body.String(), `"result":{"email":"required"}`)
+}
+
+func TestValidationAdapters(t *testing.T) {
+
+	t.Parallel()
+
+	err := fmt.Errorf("binding: %w", libraryErrors{
+		{namespace: "User.email", tag: "required"},
+		{namespace: "User.tags[1]", tag: "max", param: "10"},
+	})
+
+	v := NewValidation()
+	assert.False(t, v.AddError(errors.New("unknown")))
+	assert.True(t, v.AddError(libraryErrors{{namespace: "User.email", tag: "email"}}))
+	assert.Equal(t, []*FieldError{{Field: "/email", Rule: "email"}}, v.Errors())
+
+	recorder := httptest.NewRecorder()
+	assert.NoError(t, NewWithWriter(recorder).ValidationErrors(errors.Unwrap(err)))
+	assert.Contains(t, recorder.Body.String(), `{"field":"/tags/1","rule":"max","params":{"max":"10"},"message":"The 1 field may not be greater than 10"}`)
+
+	type adapted struct{ error }
+	RegisterValidationAdapter(ValidationAdapterFunc(func(err error) ([]*FieldError, bool) {
+		var a adapted
+		if !errors.As(err, &a) {
+			return nil, false
+		}
+		return []*FieldError{{Field: "/name", Rule: "required"}}, true
+	}))
+	v = NewValidation()
+	assert.True(t, v.AddError(fmt.Errorf("wrapped: %w", adapted{errors.New("name")})))
+	assert.Equal(t, "/name", v.Errors()[0].Field)
+}
+
+func TestValidationErrorsProblem(t *testing.T) {
+
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+	r := NewWithWriter(recorder).SetMode(ModeProblem)
+	assert.NoError(t, r.ValidationErrors(NewValidation().Add("email", "email")))
+	assert.Contains(t, recorder.Body.String(), `"errors":[{"field":"/email","rule":"email","message":"The email field must be a valid email address"}]`)
+	assert.NotEqual(t, http.StatusOK, recorder.Code)
+}